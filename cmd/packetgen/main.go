@@ -0,0 +1,536 @@
+// Command packetgen generates the ID, Read and Write methods of packet structs and their
+// registration. It is run by go generate in a packet package:
+//
+//	//go:generate go run Veloce/cmd/packetgen
+//
+// A packet is a struct whose doc comment ends in a directive naming its state and ID:
+//
+//	// PingRequestPacket asks for a pong carrying the same number.
+//	//
+//	//packet:status 0x01
+//	type PingRequestPacket struct {
+//		Number int64
+//	}
+//
+// Packets in a package named serverbound get a Read method, those in clientbound a Write
+// method. Structs marked //packet:type get Decode and Encode methods, so packets can hold
+// them directly or in arrays.
+//
+// Fields are encoded in order. The wire type is inferred from the Go type, int32 being a
+// VarInt, and can be set with an mc struct tag of the form `mc:"kind,max=N,elem=kind"`:
+//
+//	varint varlong byte bool short ushort int long float double angle
+//	string     max in UTF-16 units, common.MaxStringLength by default
+//	uuid
+//	bytes      VarInt-prefixed byte array, max required for reading
+//	rest       all remaining bytes, max optional
+//	array      VarInt-prefixed array, max required for reading, elem sets the element kind
+//	profile    common.GameProfile
+//	nbt        common.Tag
+//	teleportflags
+//	struct     a //packet:type struct, the default for types not listed here
+//
+// A field tagged mc:"-" is skipped.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	outputFile  = "packets_gen.go"
+	commonPath  = "Veloce/internal/network/common"
+	networkPath = "Veloce/internal/network"
+)
+
+var states = map[string]string{
+	"handshake":     "common.Handshake",
+	"status":        "common.Status",
+	"login":         "common.Login",
+	"configuration": "common.Configuration",
+	"play":          "common.Play",
+}
+
+var stateOrder = map[string]int{
+	"common.Handshake":     0,
+	"common.Status":        1,
+	"common.Login":         2,
+	"common.Configuration": 3,
+	"common.Play":          4,
+}
+
+// codec holds the Buffer calls for a wire type. {max} stands for the field's max option.
+type codec struct {
+	read  string
+	write string
+}
+
+var codecs = map[string]codec{
+	"varint":        {"buf.ReadVarInt()", "buf.WriteVarInt"},
+	"varlong":       {"buf.ReadVarLong()", "buf.WriteVarLong"},
+	"byte":          {"buf.ReadByte()", "buf.WriteByte"},
+	"bool":          {"buf.ReadBool()", "buf.WriteBool"},
+	"short":         {"buf.ReadInt16()", "buf.WriteInt16"},
+	"ushort":        {"buf.ReadUint16()", "buf.WriteUint16"},
+	"int":           {"buf.ReadInt32()", "buf.WriteInt32"},
+	"long":          {"buf.ReadInt64()", "buf.WriteInt64"},
+	"float":         {"buf.ReadFloat32()", "buf.WriteFloat32"},
+	"double":        {"buf.ReadFloat64()", "buf.WriteFloat64"},
+	"angle":         {"buf.ReadAngle()", "buf.WriteAngle"},
+	"string":        {"buf.ReadStringMax({max})", "buf.WriteString"},
+	"uuid":          {"buf.ReadUUID()", "buf.WriteUUID"},
+	"bytes":         {"buf.ReadByteArray({max})", "buf.WriteByteArray"},
+	"profile":       {"buf.ReadGameProfile()", "buf.WriteGameProfile"},
+	"nbt":           {"buf.ReadNBT()", "buf.WriteNBT"},
+	"teleportflags": {"buf.ReadTeleportFlags()", "buf.WriteTeleportFlags"},
+}
+
+// inferred maps Go types to the wire type used when a field has no kind in its tag.
+var inferred = map[string]string{
+	"bool":                 "bool",
+	"string":               "string",
+	"byte":                 "byte",
+	"uint8":                "byte",
+	"int16":                "short",
+	"uint16":               "ushort",
+	"int32":                "varint",
+	"int64":                "long",
+	"float32":              "float",
+	"float64":              "double",
+	"uuid.UUID":            "uuid",
+	"[]byte":               "bytes",
+	"common.GameProfile":   "profile",
+	"player.GameProfile":   "profile",
+	"common.Tag":           "nbt",
+	"common.TeleportFlags": "teleportflags",
+}
+
+type field struct {
+	name   string
+	goType string
+	kind   string
+	max    string
+	elem   *field // Element of an array
+}
+
+type packetType struct {
+	name   string
+	fields []field
+	state  string // Empty for //packet:type structs
+	id     string
+}
+
+type generator struct {
+	pkg     string
+	imports map[string]string // Qualifier to import path, from the package's files
+	types   []packetType
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("packetgen: ")
+
+	dir, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{imports: map[string]string{}}
+	if err := g.parse(dir); err != nil {
+		log.Fatal(err)
+	}
+	src, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, outputFile), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) parse(dir string) error {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == outputFile {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		g.pkg = file.Name.Name
+
+		qualifiers := map[string]string{}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := importPath[strings.LastIndex(importPath, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			qualifiers[name] = importPath
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = gen.Doc
+				}
+				directive, ok := findDirective(doc)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					return fmt.Errorf("%s: //packet: on non-struct type %s", fset.Position(typeSpec.Pos()), typeSpec.Name.Name)
+				}
+				t, err := g.parseType(typeSpec.Name.Name, directive, structType, qualifiers)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fset.Position(typeSpec.Pos()), err)
+				}
+				g.types = append(g.types, t)
+			}
+		}
+	}
+
+	if g.pkg != "serverbound" && g.pkg != "clientbound" {
+		return fmt.Errorf("package %s is neither serverbound nor clientbound", g.pkg)
+	}
+	return nil
+}
+
+func findDirective(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		if directive, ok := strings.CutPrefix(comment.Text, "//packet:"); ok {
+			return strings.TrimSpace(directive), true
+		}
+	}
+	return "", false
+}
+
+func (g *generator) parseType(name, directive string, structType *ast.StructType, qualifiers map[string]string) (packetType, error) {
+	t := packetType{name: name}
+	if directive != "type" {
+		parts := strings.Fields(directive)
+		if len(parts) != 2 {
+			return t, fmt.Errorf("directive of %s must be //packet:<state> <id> or //packet:type", name)
+		}
+		state, ok := states[parts[0]]
+		if !ok {
+			return t, fmt.Errorf("unknown state %q of %s", parts[0], name)
+		}
+		if _, err := strconv.ParseInt(parts[1], 0, 32); err != nil {
+			return t, fmt.Errorf("invalid ID %q of %s", parts[1], name)
+		}
+		t.state, t.id = state, parts[1]
+	}
+
+	for _, astField := range structType.Fields.List {
+		var tag reflect.StructTag
+		if astField.Tag != nil {
+			raw, _ := strconv.Unquote(astField.Tag.Value)
+			tag = reflect.StructTag(raw)
+		}
+		options := tag.Get("mc")
+		if options == "-" {
+			continue
+		}
+
+		goType := g.typeString(astField.Type, qualifiers)
+		for _, ident := range astField.Names {
+			f, err := parseField(ident.Name, goType, options)
+			if err != nil {
+				return t, fmt.Errorf("field %s.%s: %w", name, ident.Name, err)
+			}
+			t.fields = append(t.fields, f)
+		}
+	}
+	return t, nil
+}
+
+func parseField(name, goType, options string) (field, error) {
+	f := field{name: name, goType: goType}
+	var elemKind string
+	for i, option := range strings.Split(options, ",") {
+		switch key, value, _ := strings.Cut(option, "="); {
+		case i == 0:
+			f.kind = key
+		case key == "max":
+			f.max = value
+		case key == "elem":
+			elemKind = value
+		case key != "":
+			return f, fmt.Errorf("unknown option %q", option)
+		}
+	}
+
+	if f.kind == "" {
+		f.kind = inferKind(goType)
+	}
+	switch f.kind {
+	case "array":
+		elemType, ok := strings.CutPrefix(goType, "[]")
+		if !ok {
+			return f, fmt.Errorf("array of non-slice type %s", goType)
+		}
+		elem, err := parseField("v", elemType, elemKind)
+		if err != nil {
+			return f, err
+		}
+		f.elem = &elem
+	case "rest":
+		if goType != "[]byte" {
+			return f, fmt.Errorf("rest of non-[]byte type %s", goType)
+		}
+	case "struct":
+	default:
+		if _, ok := codecs[f.kind]; !ok {
+			return f, fmt.Errorf("unknown kind %q", f.kind)
+		}
+	}
+	if f.kind == "string" && f.max == "" {
+		f.max = "common.MaxStringLength"
+	}
+	return f, nil
+}
+
+func inferKind(goType string) string {
+	if kind, ok := inferred[goType]; ok {
+		return kind
+	}
+	if strings.HasPrefix(goType, "[]") {
+		return "array"
+	}
+	return "struct"
+}
+
+// typeString prints a field type, recording the imports it needs. The common package is
+// always referred to as common, whatever the source file calls it.
+func (g *generator) typeString(expr ast.Expr, qualifiers map[string]string) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return "*" + g.typeString(e.X, qualifiers)
+	case *ast.ArrayType:
+		return "[]" + g.typeString(e.Elt, qualifiers)
+	case *ast.SelectorExpr:
+		qualifier := e.X.(*ast.Ident).Name
+		importPath := qualifiers[qualifier]
+		if importPath == commonPath {
+			qualifier = "common"
+		}
+		g.imports[qualifier] = importPath
+		return qualifier + "." + e.Sel.Name
+	}
+	log.Fatalf("unsupported field type %T", expr)
+	return ""
+}
+
+func (g *generator) generate() ([]byte, error) {
+	var body bytes.Buffer
+
+	for _, t := range g.types {
+		if t.state != "" {
+			fmt.Fprintf(&body, "func (p *%s) ID() int32 {\n\treturn %s\n}\n\n", t.name, t.id)
+		}
+		if t.state == "" || g.pkg == "serverbound" {
+			method := "Read"
+			if t.state == "" {
+				method = "Decode"
+			}
+			code, err := readMethod(t, method)
+			if err != nil {
+				return nil, err
+			}
+			body.WriteString(code)
+		}
+		if t.state == "" || g.pkg == "clientbound" {
+			method := "Write"
+			if t.state == "" {
+				method = "Encode"
+			}
+			body.WriteString(writeMethod(t, method))
+		}
+	}
+
+	body.WriteString(registerFunc(g.pkg, g.types))
+	g.imports["common"] = commonPath
+	g.imports["fmt"] = "fmt"
+	g.imports["network"] = networkPath
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by packetgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", g.pkg)
+	// Import only what the generated code refers to
+	var qualifiers []string
+	for qualifier := range g.imports {
+		if regexp.MustCompile(`\b` + qualifier + `\.`).Match(body.Bytes()) {
+			qualifiers = append(qualifiers, qualifier)
+		}
+	}
+	sort.Slice(qualifiers, func(i, j int) bool { return g.imports[qualifiers[i]] < g.imports[qualifiers[j]] })
+	for _, qualifier := range qualifiers {
+		importPath := g.imports[qualifier]
+		if importPath[strings.LastIndex(importPath, "/")+1:] == qualifier {
+			fmt.Fprintf(&out, "\t%q\n", importPath)
+		} else {
+			fmt.Fprintf(&out, "\t%s %q\n", qualifier, importPath)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+func readMethod(t packetType, method string) (string, error) {
+	if len(t.fields) == 0 {
+		return fmt.Sprintf("func (p *%s) %s(*common.Buffer) error {\n\treturn nil\n}\n\n", t.name, method), nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func (p *%s) %s(buf *common.Buffer) error {\n\tvar err error\n", t.name, method)
+	for _, f := range t.fields {
+		target := "p." + f.name
+		wrap := fmt.Sprintf("{\n\t\treturn fmt.Errorf(\"%s: %%w\", err)\n\t}\n", f.name)
+
+		switch f.kind {
+		case "struct":
+			fmt.Fprintf(&b, "\tif err = %s.Decode(buf); err != nil %s", target, wrap)
+		case "rest":
+			if f.max != "" {
+				fmt.Fprintf(&b, "\tif buf.Len() > %s {\n\t\treturn fmt.Errorf(\"%s: %%d bytes, at most %%d allowed\", buf.Len(), %s)\n\t}\n", f.max, f.name, f.max)
+			}
+			fmt.Fprintf(&b, "\t%s = append([]byte(nil), buf.Next(buf.Len())...)\n", target)
+		case "array":
+			if f.max == "" {
+				return "", fmt.Errorf("field %s.%s: arrays need a max to be read", t.name, f.name)
+			}
+			elem, err := readElem(*f.elem)
+			if err != nil {
+				return "", fmt.Errorf("field %s.%s: %w", t.name, f.name, err)
+			}
+			fmt.Fprintf(&b, "\tif %s, err = common.ReadPrefixedArray(buf, %s, func(buf *common.Buffer) (%s, error) {\n%s\t}); err != nil %s",
+				target, f.max, f.elem.goType, elem, wrap)
+		default:
+			read, err := readExpr(f)
+			if err != nil {
+				return "", fmt.Errorf("field %s.%s: %w", t.name, f.name, err)
+			}
+			fmt.Fprintf(&b, "\tif %s, err = %s; err != nil %s", target, read, wrap)
+		}
+	}
+	b.WriteString("\treturn nil\n}\n\n")
+	return b.String(), nil
+}
+
+func readElem(f field) (string, error) {
+	switch f.kind {
+	case "struct":
+		return fmt.Sprintf("\t\tvar v %s\n\t\terr := v.Decode(buf)\n\t\treturn v, err\n", f.goType), nil
+	case "array", "rest":
+		return "", fmt.Errorf("arrays of %s are not supported", f.kind)
+	}
+	read, err := readExpr(f)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\t\treturn %s\n", read), nil
+}
+
+func readExpr(f field) (string, error) {
+	c := codecs[f.kind]
+	if strings.Contains(c.read, "{max}") && f.max == "" {
+		return "", fmt.Errorf("%s needs a max to be read", f.kind)
+	}
+	return strings.ReplaceAll(c.read, "{max}", f.max), nil
+}
+
+func writeMethod(t packetType, method string) string {
+	if len(t.fields) == 0 {
+		return fmt.Sprintf("func (p *%s) %s(*common.Buffer) {\n}\n\n", t.name, method)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func (p *%s) %s(buf *common.Buffer) {\n", t.name, method)
+	for _, f := range t.fields {
+		if f.kind == "array" {
+			fmt.Fprintf(&b, "\tbuf.WriteVarInt(int32(len(p.%s)))\n\tfor _, v := range p.%s {\n\t\t%s\n\t}\n",
+				f.name, f.name, writeStmt(*f.elem, "v"))
+			continue
+		}
+		fmt.Fprintf(&b, "\t%s\n", writeStmt(f, "p."+f.name))
+	}
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+func writeStmt(f field, value string) string {
+	switch f.kind {
+	case "struct":
+		return value + ".Encode(buf)"
+	case "rest":
+		return "buf.Write(" + value + ")"
+	}
+	return codecs[f.kind].write + "(" + value + ")"
+}
+
+func registerFunc(pkg string, types []packetType) string {
+	var b strings.Builder
+	direction, packetInterface := "ServerBound", "ServerboundPacket"
+	if pkg == "clientbound" {
+		direction, packetInterface = "ClientBound", "ClientboundPacket"
+	}
+	fmt.Fprintf(&b, "// RegisterPackets registers every generated %s packet with reg.\n", pkg)
+	b.WriteString("func RegisterPackets(reg *network.PacketRegistry) {\n")
+	var packets []packetType
+	for _, t := range types {
+		if t.state != "" {
+			packets = append(packets, t)
+		}
+	}
+	sort.SliceStable(packets, func(i, j int) bool {
+		if packets[i].state != packets[j].state {
+			return stateOrder[packets[i].state] < stateOrder[packets[j].state]
+		}
+		a, _ := strconv.ParseInt(packets[i].id, 0, 32)
+		b, _ := strconv.ParseInt(packets[j].id, 0, 32)
+		return a < b
+	})
+	for _, t := range packets {
+		fmt.Fprintf(&b, "\treg.Register%s(%s, %s, func() common.%s { return &%s{} })\n", direction, t.state, t.id, packetInterface, t.name)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
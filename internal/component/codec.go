@@ -0,0 +1,301 @@
+package component
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+)
+
+// The JSON and NBT formats share one structure. Components are encoded into a tree of
+// map[string]any, []any, string, bool, int32 and []int32 first, which each format then
+// converts. Decoding works the other way round, with every number as float64.
+
+// ErrInvalidComponent is returned for input that does not describe a component.
+var ErrInvalidComponent = errors.New("invalid component")
+
+// encode returns the tree for c. Plain text becomes a bare string if allowString is set;
+// NBT lists must not mix strings and compounds, so children never use the shorthand there.
+func (c Component) encode(allowString bool) any {
+	if allowString && c.isPlainText() {
+		return c.Content.(TextContent).Text
+	}
+
+	m := make(map[string]any)
+	switch content := c.Content.(type) {
+	case TranslatableContent:
+		m["translate"] = content.Key
+		if content.Fallback != "" {
+			m["fallback"] = content.Fallback
+		}
+		if len(content.Args) > 0 {
+			m["with"] = encodeList(content.Args, allowString)
+		}
+	case KeybindContent:
+		m["keybind"] = content.Key
+	case ScoreContent:
+		m["score"] = map[string]any{"name": content.Name, "objective": content.Objective}
+	case SelectorContent:
+		m["selector"] = content.Pattern
+		if content.Separator != nil {
+			m["separator"] = content.Separator.encode(allowString)
+		}
+	case TextContent:
+		m["text"] = content.Text
+	default:
+		m["text"] = ""
+	}
+
+	c.Style.encode(m, allowString)
+	if len(c.Children) > 0 {
+		m["extra"] = encodeList(c.Children, allowString)
+	}
+	return m
+}
+
+func encodeList(components []Component, allowString bool) []any {
+	list := make([]any, len(components))
+	for i, c := range components {
+		list[i] = c.encode(allowString)
+	}
+	return list
+}
+
+func (s Style) encode(m map[string]any, allowString bool) {
+	if s.Color != nil {
+		m["color"] = s.Color.String()
+	}
+	for _, d := range Decorations {
+		if state := s.Decoration(d); state != Unset {
+			m[d.String()] = state == True
+		}
+	}
+	if s.Font != "" {
+		m["font"] = s.Font
+	}
+	if s.Insertion != "" {
+		m["insertion"] = s.Insertion
+	}
+
+	if click := s.ClickEvent; click != nil {
+		event := map[string]any{"action": string(click.Action)}
+		if click.Action == ChangePage {
+			page, _ := strconv.ParseInt(click.Value, 10, 32)
+			event["page"] = int32(page)
+		} else {
+			event[click.Action.valueKey()] = click.Value
+		}
+		m["click_event"] = event
+	}
+
+	if hover := s.HoverEvent; hover != nil {
+		event := map[string]any{"action": string(hover.Action)}
+		switch {
+		case hover.Action == ShowText && hover.Text != nil:
+			event["value"] = hover.Text.encode(allowString)
+		case hover.Action == ShowItem && hover.Item != nil:
+			event["id"] = hover.Item.ID
+			event["count"] = hover.Item.Count
+		case hover.Action == ShowEntity && hover.Entity != nil:
+			event["id"] = hover.Entity.Type
+			event["uuid"] = uuidToInts(hover.Entity.UUID)
+			if hover.Entity.Name != nil {
+				event["name"] = hover.Entity.Name.encode(allowString)
+			}
+		}
+		m["hover_event"] = event
+	}
+}
+
+// decode builds a component from a tree produced by either format.
+func decode(v any) (Component, error) {
+	switch v := v.(type) {
+	case string:
+		return Text(v), nil
+	case bool, float64:
+		return Text(fmt.Sprint(v)), nil
+	case []any:
+		// An array is its first element followed by the rest as children
+		if len(v) == 0 {
+			return Component{}, fmt.Errorf("%w: empty array", ErrInvalidComponent)
+		}
+		c, err := decode(v[0])
+		if err != nil {
+			return Component{}, err
+		}
+		children, err := decodeList(v[1:])
+		if err != nil {
+			return Component{}, err
+		}
+		return c.Append(children...), nil
+	case map[string]any:
+		return decodeObject(v)
+	}
+	return Component{}, fmt.Errorf("%w: unexpected %T", ErrInvalidComponent, v)
+}
+
+func decodeList(list []any) ([]Component, error) {
+	components := make([]Component, 0, len(list))
+	for _, v := range list {
+		c, err := decode(v)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, c)
+	}
+	return components, nil
+}
+
+func decodeObject(m map[string]any) (Component, error) {
+	// Vanilla wraps list elements that are not compounds as {"": value}
+	if value, ok := m[""]; ok && len(m) == 1 {
+		return decode(value)
+	}
+
+	var c Component
+	var err error
+	switch {
+	case m["text"] != nil:
+		c = Text(fmt.Sprint(m["text"]))
+	case m["translate"] != nil:
+		content := TranslatableContent{Key: stringOf(m["translate"]), Fallback: stringOf(m["fallback"])}
+		if with, ok := m["with"].([]any); ok {
+			if content.Args, err = decodeList(with); err != nil {
+				return Component{}, err
+			}
+		}
+		c.Content = content
+	case m["keybind"] != nil:
+		c = Keybind(stringOf(m["keybind"]))
+	case m["score"] != nil:
+		score, _ := m["score"].(map[string]any)
+		c = Score(stringOf(score["name"]), stringOf(score["objective"]))
+	case m["selector"] != nil:
+		content := SelectorContent{Pattern: stringOf(m["selector"])}
+		if separator, ok := m["separator"]; ok {
+			sep, err := decode(separator)
+			if err != nil {
+				return Component{}, err
+			}
+			content.Separator = &sep
+		}
+		c.Content = content
+	default:
+		return Component{}, fmt.Errorf("%w: unsupported content", ErrInvalidComponent)
+	}
+
+	if c.Style, err = decodeStyle(m); err != nil {
+		return Component{}, err
+	}
+	if extra, ok := m["extra"].([]any); ok {
+		if c.Children, err = decodeList(extra); err != nil {
+			return Component{}, err
+		}
+	}
+	return c, nil
+}
+
+func decodeStyle(m map[string]any) (Style, error) {
+	var s Style
+	if name, ok := m["color"].(string); ok {
+		color, err := ParseColor(name)
+		if err != nil {
+			return Style{}, fmt.Errorf("%w: %v", ErrInvalidComponent, err)
+		}
+		s.Color = &color
+	}
+	for _, d := range Decorations {
+		if v, ok := boolOf(m[d.String()]); ok {
+			s.SetDecoration(d, StateOf(v))
+		}
+	}
+	s.Font = stringOf(m["font"])
+	s.Insertion = stringOf(m["insertion"])
+
+	if event, ok := m["click_event"].(map[string]any); ok {
+		action := ClickAction(stringOf(event["action"]))
+		s.ClickEvent = &ClickEvent{Action: action, Value: stringOf(event[action.valueKey()])}
+	}
+
+	if event, ok := m["hover_event"].(map[string]any); ok {
+		hover := &HoverEvent{Action: HoverAction(stringOf(event["action"]))}
+		switch hover.Action {
+		case ShowText:
+			text, err := decode(event["value"])
+			if err != nil {
+				return Style{}, err
+			}
+			hover.Text = &text
+		case ShowItem:
+			count, ok := event["count"].(float64)
+			if !ok {
+				count = 1
+			}
+			hover.Item = &HoverItem{ID: stringOf(event["id"]), Count: int32(count)}
+		case ShowEntity:
+			hover.Entity = &HoverEntity{Type: stringOf(event["id"]), UUID: uuidOf(event["uuid"])}
+			if name, ok := event["name"]; ok {
+				c, err := decode(name)
+				if err != nil {
+					return Style{}, err
+				}
+				hover.Entity.Name = &c
+			}
+		}
+		s.HoverEvent = hover
+	}
+	return s, nil
+}
+
+func stringOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// boolOf accepts JSON booleans as well as the bytes NBT stores booleans as.
+func boolOf(v any) (bool, bool) {
+	switch v := v.(type) {
+	case bool:
+		return v, true
+	case float64:
+		return v != 0, true
+	}
+	return false, false
+}
+
+// uuidToInts returns the UUID as four big-endian ints, the form both formats use.
+func uuidToInts(id uuid.UUID) []int32 {
+	ints := make([]int32, 4)
+	for i := range ints {
+		ints[i] = int32(binary.BigEndian.Uint32(id[i*4:]))
+	}
+	return ints
+}
+
+// uuidOf accepts a UUID as four ints or as a string.
+func uuidOf(v any) uuid.UUID {
+	switch v := v.(type) {
+	case string:
+		id, _ := uuid.Parse(v)
+		return id
+	case []any:
+		var id uuid.UUID
+		if len(v) != 4 {
+			return id
+		}
+		for i, part := range v {
+			n, _ := part.(float64)
+			binary.BigEndian.PutUint32(id[i*4:], uint32(int32(n)))
+		}
+		return id
+	}
+	return uuid.UUID{}
+}
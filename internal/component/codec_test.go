@@ -0,0 +1,180 @@
+package component
+
+import (
+	"Veloce/internal/network/common"
+	"errors"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+)
+
+func TestComponentRoundTrip(t *testing.T) {
+	name := Text("Steve").Color(Yellow)
+	id := uuid.MustParse("069a79f4-44e9-4726-a5be-fca90e38aaf5")
+
+	tests := []struct {
+		name string
+		c    Component
+		json string
+	}{
+		{name: "plain text is a string", c: Text("hi"), json: `"hi"`},
+		{name: "empty text", c: Empty(), json: `""`},
+		{name: "HTML characters are not escaped", c: Text("<a & b>"), json: `"<a & b>"`},
+		{name: "styled text", c: Text("hi").Color(Red), json: `{"color":"red","text":"hi"}`},
+		{name: "hex color", c: Text("hi").Color(Hex(0x12AB34)), json: `{"color":"#12AB34","text":"hi"}`},
+		{
+			name: "decorations",
+			c:    Text("hi").Decorate(Bold, Italic).Decoration(Underlined, False),
+			json: `{"bold":true,"italic":true,"text":"hi","underlined":false}`,
+		},
+		{
+			name: "plain children are strings",
+			c:    Text("a").Append(Text("b"), Text("c").Color(Gold)),
+			json: `{"extra":["b",{"color":"gold","text":"c"}],"text":"a"}`,
+		},
+		{
+			name: "font and insertion",
+			c:    Text("hi").Font("minecraft:uniform").Insertion("inserted"),
+			json: `{"font":"minecraft:uniform","insertion":"inserted","text":"hi"}`,
+		},
+		{
+			name: "translatable",
+			c:    Translatable("chat.type.text", name, Text("hello")),
+			json: `{"translate":"chat.type.text","with":[{"color":"yellow","text":"Steve"},"hello"]}`,
+		},
+		{
+			name: "translatable with fallback",
+			c:    Component{Content: TranslatableContent{Key: "custom.key", Fallback: "Custom"}},
+			json: `{"fallback":"Custom","translate":"custom.key"}`,
+		},
+		{name: "keybind", c: Keybind("key.jump"), json: `{"keybind":"key.jump"}`},
+		{name: "score", c: Score("@s", "kills"), json: `{"score":{"name":"@s","objective":"kills"}}`},
+		{
+			name: "selector with separator",
+			c:    Component{Content: SelectorContent{Pattern: "@a", Separator: &name}},
+			json: `{"selector":"@a","separator":{"color":"yellow","text":"Steve"}}`,
+		},
+		{
+			name: "run command click event",
+			c:    Text("x").Click(RunCommand, "/help"),
+			json: `{"click_event":{"action":"run_command","command":"/help"},"text":"x"}`,
+		},
+		{
+			name: "open URL click event",
+			c:    Text("x").Click(OpenURL, "https://example.com"),
+			json: `{"click_event":{"action":"open_url","url":"https://example.com"},"text":"x"}`,
+		},
+		{
+			name: "change page click event",
+			c:    Text("x").Click(ChangePage, "3"),
+			json: `{"click_event":{"action":"change_page","page":3},"text":"x"}`,
+		},
+		{
+			name: "copy to clipboard click event",
+			c:    Text("x").Click(CopyToClipboard, "copied"),
+			json: `{"click_event":{"action":"copy_to_clipboard","value":"copied"},"text":"x"}`,
+		},
+		{
+			name: "show text hover event",
+			c:    Text("x").Hover(ShowTextEvent(Text("tip").Color(Gray))),
+			json: `{"hover_event":{"action":"show_text","value":{"color":"gray","text":"tip"}},"text":"x"}`,
+		},
+		{
+			name: "show item hover event",
+			c:    Text("x").Hover(ShowItemEvent("minecraft:diamond", 3)),
+			json: `{"hover_event":{"action":"show_item","count":3,"id":"minecraft:diamond"},"text":"x"}`,
+		},
+		{
+			name: "show entity hover event",
+			c:    Text("x").Hover(ShowEntityEvent("minecraft:player", id, &name)),
+			json: `{"hover_event":{"action":"show_entity","id":"minecraft:player",` +
+				`"name":{"color":"yellow","text":"Steve"},"uuid":[110787060,1156138790,-1514210135,238594805]},"text":"x"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.JSON(); got != tt.json {
+				t.Errorf("JSON() = %s, want %s", got, tt.json)
+			}
+			fromJSON, err := ParseJSON(tt.json)
+			if err != nil {
+				t.Fatalf("ParseJSON: %v", err)
+			}
+			if !reflect.DeepEqual(fromJSON, tt.c) {
+				t.Errorf("ParseJSON = %#v, want %#v", fromJSON, tt.c)
+			}
+
+			// Through the wire, as packets carry it
+			buf := common.NewBuffer(nil)
+			if err := buf.WriteNBT(tt.c.NBT()); err != nil {
+				t.Fatalf("write NBT: %v", err)
+			}
+			tag, err := buf.ReadNBT()
+			if err != nil {
+				t.Fatalf("read NBT: %v", err)
+			}
+			fromNBT, err := FromNBT(tag)
+			if err != nil {
+				t.Fatalf("FromNBT: %v", err)
+			}
+			if !reflect.DeepEqual(fromNBT, tt.c) {
+				t.Errorf("FromNBT = %#v, want %#v", fromNBT, tt.c)
+			}
+		})
+	}
+}
+
+func TestParseJSONShorthands(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Component
+	}{
+		{name: "array", json: `["a",{"text":"b","bold":true}]`, want: Text("a").Append(Text("b").Decorate(Bold))},
+		{name: "number", json: `42`, want: Text("42")},
+		{name: "boolean", json: `true`, want: Text("true")},
+		{name: "numeric text", json: `{"text":1.5}`, want: Text("1.5")},
+		{name: "unknown keys are ignored", json: `{"text":"a","unknown":1}`, want: Text("a")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSON(tt.json)
+			if err != nil {
+				t.Fatalf("ParseJSON: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr error // Checked with errors.Is when set
+	}{
+		{name: "malformed JSON", json: `{"text":`},
+		{name: "null", json: `null`, wantErr: ErrInvalidComponent},
+		{name: "empty array", json: `[]`, wantErr: ErrInvalidComponent},
+		{name: "no content", json: `{"color":"red"}`, wantErr: ErrInvalidComponent},
+		{name: "unknown color", json: `{"text":"a","color":"crimson"}`, wantErr: ErrInvalidComponent},
+		{name: "bad child", json: `{"text":"a","extra":[null]}`, wantErr: ErrInvalidComponent},
+		{name: "bad hover text", json: `{"text":"a","hover_event":{"action":"show_text"}}`, wantErr: ErrInvalidComponent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseJSON(tt.json)
+			if err == nil {
+				t.Fatalf("parsed %#v", c)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
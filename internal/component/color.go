@@ -0,0 +1,107 @@
+package component
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TextColor is an RGB text color. The sixteen named colors keep their name when serialized.
+type TextColor struct {
+	RGB  int32
+	name string
+	code rune
+}
+
+var (
+	Black       = TextColor{RGB: 0x000000, name: "black", code: '0'}
+	DarkBlue    = TextColor{RGB: 0x0000AA, name: "dark_blue", code: '1'}
+	DarkGreen   = TextColor{RGB: 0x00AA00, name: "dark_green", code: '2'}
+	DarkAqua    = TextColor{RGB: 0x00AAAA, name: "dark_aqua", code: '3'}
+	DarkRed     = TextColor{RGB: 0xAA0000, name: "dark_red", code: '4'}
+	DarkPurple  = TextColor{RGB: 0xAA00AA, name: "dark_purple", code: '5'}
+	Gold        = TextColor{RGB: 0xFFAA00, name: "gold", code: '6'}
+	Gray        = TextColor{RGB: 0xAAAAAA, name: "gray", code: '7'}
+	DarkGray    = TextColor{RGB: 0x555555, name: "dark_gray", code: '8'}
+	Blue        = TextColor{RGB: 0x5555FF, name: "blue", code: '9'}
+	Green       = TextColor{RGB: 0x55FF55, name: "green", code: 'a'}
+	Aqua        = TextColor{RGB: 0x55FFFF, name: "aqua", code: 'b'}
+	Red         = TextColor{RGB: 0xFF5555, name: "red", code: 'c'}
+	LightPurple = TextColor{RGB: 0xFF55FF, name: "light_purple", code: 'd'}
+	Yellow      = TextColor{RGB: 0xFFFF55, name: "yellow", code: 'e'}
+	White       = TextColor{RGB: 0xFFFFFF, name: "white", code: 'f'}
+)
+
+// NamedColors lists the named colors in legacy code order.
+var NamedColors = []TextColor{
+	Black, DarkBlue, DarkGreen, DarkAqua, DarkRed, DarkPurple, Gold, Gray,
+	DarkGray, Blue, Green, Aqua, Red, LightPurple, Yellow, White,
+}
+
+// Hex returns the color for a 0xRRGGBB value.
+func Hex(rgb int32) TextColor {
+	return TextColor{RGB: rgb & 0xFFFFFF}
+}
+
+// ParseColor parses a color name such as "dark_red" or a "#RRGGBB" hex color.
+func ParseColor(s string) (TextColor, error) {
+	if strings.HasPrefix(s, "#") {
+		if len(s) != 7 {
+			return TextColor{}, fmt.Errorf("invalid hex color %q", s)
+		}
+		rgb, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return TextColor{}, fmt.Errorf("invalid hex color %q", s)
+		}
+		return Hex(int32(rgb)), nil
+	}
+
+	for _, color := range NamedColors {
+		if color.name == s {
+			return color, nil
+		}
+	}
+	return TextColor{}, fmt.Errorf("unknown color %q", s)
+}
+
+// colorByCode returns the named color for a legacy formatting code.
+func colorByCode(code rune) (TextColor, bool) {
+	for _, color := range NamedColors {
+		if color.code == code {
+			return color, true
+		}
+	}
+	return TextColor{}, false
+}
+
+// IsNamed reports whether c is one of the sixteen named colors.
+func (c TextColor) IsNamed() bool {
+	return c.name != ""
+}
+
+// Nearest returns the named color closest to c.
+func (c TextColor) Nearest() TextColor {
+	if c.IsNamed() {
+		return c
+	}
+
+	nearest, best := White, int32(-1)
+	for _, named := range NamedColors {
+		dr := (c.RGB>>16)&0xFF - (named.RGB>>16)&0xFF
+		dg := (c.RGB>>8)&0xFF - (named.RGB>>8)&0xFF
+		db := c.RGB&0xFF - named.RGB&0xFF
+		distance := dr*dr + dg*dg + db*db
+		if best < 0 || distance < best {
+			nearest, best = named, distance
+		}
+	}
+	return nearest
+}
+
+// String returns the color's name, or #RRGGBB for other colors.
+func (c TextColor) String() string {
+	if c.name != "" {
+		return c.name
+	}
+	return fmt.Sprintf("#%06X", c.RGB)
+}
@@ -0,0 +1,188 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Component is a piece of formatted text as used by chat, titles, kick messages and the
+// server list. Children inherit the style of their parent unless they override it.
+type Component struct {
+	Content  Content
+	Style    Style
+	Children []Component
+}
+
+// Content is what a component displays before its children.
+type Content interface {
+	isContent()
+}
+
+// TextContent displays literal text.
+type TextContent struct {
+	Text string
+}
+
+// TranslatableContent displays a translation key from the client's language, with
+// %s placeholders filled by Args. Fallback is shown for unknown keys.
+type TranslatableContent struct {
+	Key      string
+	Fallback string
+	Args     []Component
+}
+
+// KeybindContent displays the key bound to a control, such as "key.jump".
+type KeybindContent struct {
+	Key string
+}
+
+// ScoreContent displays the score of Name in Objective.
+type ScoreContent struct {
+	Name      string
+	Objective string
+}
+
+// SelectorContent displays the names of the entities matched by an entity selector.
+type SelectorContent struct {
+	Pattern   string
+	Separator *Component
+}
+
+func (TextContent) isContent()         {}
+func (TranslatableContent) isContent() {}
+func (KeybindContent) isContent()      {}
+func (ScoreContent) isContent()        {}
+func (SelectorContent) isContent()     {}
+
+// Text creates a text component.
+func Text(text string) Component {
+	return Component{Content: TextContent{Text: text}}
+}
+
+// Textf creates a text component from a format string.
+func Textf(format string, args ...any) Component {
+	return Text(fmt.Sprintf(format, args...))
+}
+
+// Empty creates a text component without text, to hold children.
+func Empty() Component {
+	return Text("")
+}
+
+// Translatable creates a component translated by the client.
+func Translatable(key string, args ...Component) Component {
+	return Component{Content: TranslatableContent{Key: key, Args: args}}
+}
+
+// Keybind creates a component showing the key bound to a control.
+func Keybind(key string) Component {
+	return Component{Content: KeybindContent{Key: key}}
+}
+
+// Score creates a component showing a scoreboard score.
+func Score(name, objective string) Component {
+	return Component{Content: ScoreContent{Name: name, Objective: objective}}
+}
+
+// Selector creates a component showing the entities matched by pattern.
+func Selector(pattern string) Component {
+	return Component{Content: SelectorContent{Pattern: pattern}}
+}
+
+// Color returns c with its color set.
+func (c Component) Color(color TextColor) Component {
+	c.Style.Color = &color
+	return c
+}
+
+// Decorate returns c with the given decorations switched on.
+func (c Component) Decorate(decorations ...Decoration) Component {
+	for _, d := range decorations {
+		c.Style.SetDecoration(d, True)
+	}
+	return c
+}
+
+// Decoration returns c with the state of a decoration set.
+func (c Component) Decoration(d Decoration, state TriState) Component {
+	c.Style.SetDecoration(d, state)
+	return c
+}
+
+// Font returns c with its font set.
+func (c Component) Font(font string) Component {
+	c.Style.Font = font
+	return c
+}
+
+// Insertion returns c with the text inserted into chat when it is shift-clicked.
+func (c Component) Insertion(text string) Component {
+	c.Style.Insertion = text
+	return c
+}
+
+// Click returns c with its click event set.
+func (c Component) Click(action ClickAction, value string) Component {
+	c.Style.ClickEvent = &ClickEvent{Action: action, Value: value}
+	return c
+}
+
+// Hover returns c with its hover event set.
+func (c Component) Hover(event *HoverEvent) Component {
+	c.Style.HoverEvent = event
+	return c
+}
+
+// Append returns c with children added after its existing ones.
+func (c Component) Append(children ...Component) Component {
+	c.Children = append(c.Children[:len(c.Children):len(c.Children)], children...)
+	return c
+}
+
+// isPlainText reports whether c is just text, which both formats may encode as a bare string.
+func (c Component) isPlainText() bool {
+	_, ok := c.Content.(TextContent)
+	return ok && c.Style.IsEmpty() && len(c.Children) == 0
+}
+
+// PlainText returns the text of c and its children without formatting. Content resolved
+// by the client is shown by its key.
+func (c Component) PlainText() string {
+	var sb strings.Builder
+	c.walk(Style{}, func(text string, _ Style) {
+		sb.WriteString(text)
+	})
+	return sb.String()
+}
+
+// walk calls fn with the text of c and its descendants in display order, each with its
+// effective style.
+func (c Component) walk(parent Style, fn func(text string, style Style)) {
+	style := c.Style.Merge(parent)
+
+	switch content := c.Content.(type) {
+	case TextContent:
+		fn(content.Text, style)
+	case TranslatableContent:
+		if content.Fallback != "" {
+			fn(content.Fallback, style)
+		} else {
+			fn(content.Key, style)
+		}
+	case KeybindContent:
+		fn(content.Key, style)
+	case ScoreContent:
+		fn(content.Name, style)
+	case SelectorContent:
+		fn(content.Pattern, style)
+	}
+
+	for _, child := range c.Children {
+		child.walk(style, fn)
+	}
+}
+
+// String returns the plain text of c.
+func (c Component) String() string {
+	return c.PlainText()
+}
@@ -0,0 +1,82 @@
+package component
+
+import "github.com/google/uuid"
+
+// ClickAction is what happens when the client clicks a component.
+type ClickAction string
+
+const (
+	OpenURL         ClickAction = "open_url"
+	OpenFile        ClickAction = "open_file"
+	RunCommand      ClickAction = "run_command"
+	SuggestCommand  ClickAction = "suggest_command"
+	ChangePage      ClickAction = "change_page"
+	CopyToClipboard ClickAction = "copy_to_clipboard"
+)
+
+// valueKey returns the field holding the value of the action.
+func (a ClickAction) valueKey() string {
+	switch a {
+	case OpenURL:
+		return "url"
+	case OpenFile:
+		return "path"
+	case RunCommand, SuggestCommand:
+		return "command"
+	case ChangePage:
+		return "page"
+	default:
+		return "value"
+	}
+}
+
+// ClickEvent is a click action with its value. Page numbers are kept as decimal strings.
+type ClickEvent struct {
+	Action ClickAction
+	Value  string
+}
+
+// HoverAction is what is shown when the client hovers over a component.
+type HoverAction string
+
+const (
+	ShowText   HoverAction = "show_text"
+	ShowItem   HoverAction = "show_item"
+	ShowEntity HoverAction = "show_entity"
+)
+
+// HoverEvent is a hover action. Only the field belonging to Action is used.
+type HoverEvent struct {
+	Action HoverAction
+	Text   *Component
+	Item   *HoverItem
+	Entity *HoverEntity
+}
+
+// HoverItem is the item shown by ShowItem.
+type HoverItem struct {
+	ID    string
+	Count int32
+}
+
+// HoverEntity is the entity shown by ShowEntity.
+type HoverEntity struct {
+	Type string
+	UUID uuid.UUID
+	Name *Component
+}
+
+// ShowTextEvent creates a hover event showing text.
+func ShowTextEvent(text Component) *HoverEvent {
+	return &HoverEvent{Action: ShowText, Text: &text}
+}
+
+// ShowItemEvent creates a hover event showing an item stack.
+func ShowItemEvent(id string, count int32) *HoverEvent {
+	return &HoverEvent{Action: ShowItem, Item: &HoverItem{ID: id, Count: count}}
+}
+
+// ShowEntityEvent creates a hover event showing an entity. name may be nil.
+func ShowEntityEvent(entityType string, id uuid.UUID, name *Component) *HoverEvent {
+	return &HoverEvent{Action: ShowEntity, Entity: &HoverEntity{Type: entityType, UUID: id, Name: name}}
+}
@@ -0,0 +1,45 @@
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes c in the JSON text format used by the server list.
+func (c Component) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// Text such as "<" must reach the client unchanged
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(c.encode(true)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// UnmarshalJSON decodes a component from a JSON string, array or object.
+func (c *Component) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	decoded, err := decode(v)
+	if err != nil {
+		return err
+	}
+	*c = decoded
+	return nil
+}
+
+// JSON returns c in the JSON text format.
+func (c Component) JSON() string {
+	data, _ := c.MarshalJSON()
+	return string(data)
+}
+
+// ParseJSON decodes a component from the JSON text format.
+func ParseJSON(s string) (Component, error) {
+	var c Component
+	err := c.UnmarshalJSON([]byte(s))
+	return c, err
+}
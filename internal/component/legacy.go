@@ -0,0 +1,138 @@
+package component
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SectionChar starts a legacy formatting code, as in "§cRed".
+const SectionChar = '§'
+
+// ParseLegacy converts text with legacy formatting codes introduced by char, such as
+// '§' or '&', into a component. A color code resets all decorations, as it does in the
+// client, and "§x§r§r§g§g§b§b" selects a hex color.
+func ParseLegacy(text string, char rune) Component {
+	root := Empty()
+	var style Style
+	var sb strings.Builder
+
+	flush := func() {
+		if sb.Len() > 0 {
+			root.Children = append(root.Children, Component{Content: TextContent{Text: sb.String()}, Style: style})
+			sb.Reset()
+		}
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != char || i+1 >= len(runes) {
+			sb.WriteRune(runes[i])
+			continue
+		}
+
+		code := unicode.ToLower(runes[i+1])
+		if color, ok := colorByCode(code); ok {
+			flush()
+			style = Style{Color: &color}
+			i++
+		} else if d, ok := decorationByCode(code); ok {
+			flush()
+			style.SetDecoration(d, True)
+			i++
+		} else if code == 'r' {
+			flush()
+			style = Style{}
+			i++
+		} else if color, ok := legacyHex(runes[i:], char); ok {
+			flush()
+			style = Style{Color: &color}
+			i += 13
+		} else {
+			sb.WriteRune(runes[i])
+		}
+	}
+	flush()
+
+	if len(root.Children) == 1 {
+		return root.Children[0]
+	}
+	return root
+}
+
+// legacyHex parses "§x§r§r§g§g§b§b" at the start of runes.
+func legacyHex(runes []rune, char rune) (TextColor, bool) {
+	if len(runes) < 14 || unicode.ToLower(runes[1]) != 'x' {
+		return TextColor{}, false
+	}
+
+	var rgb int32
+	for i := 2; i < 14; i += 2 {
+		if runes[i] != char {
+			return TextColor{}, false
+		}
+		digit := strings.IndexRune("0123456789abcdef", unicode.ToLower(runes[i+1]))
+		if digit < 0 {
+			return TextColor{}, false
+		}
+		rgb = rgb<<4 | int32(digit)
+	}
+	return Hex(rgb), true
+}
+
+func decorationByCode(code rune) (Decoration, bool) {
+	for _, d := range Decorations {
+		if d.legacyCode() == code {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Legacy returns c as text with legacy formatting codes introduced by char, for clients
+// that predate components. Hex colors are replaced by the nearest named color, and
+// everything but colors and decorations is lost.
+func (c Component) Legacy(char rune) string {
+	var sb strings.Builder
+	var current Style
+
+	c.walk(Style{}, func(text string, style Style) {
+		if text == "" {
+			return
+		}
+		if !style.legacyEqual(current) {
+			// Colors reset decorations in the client, so every change starts from scratch
+			if style.Color != nil {
+				sb.WriteRune(char)
+				sb.WriteRune(style.Color.Nearest().code)
+			} else if !(Style{}).legacyEqual(current) {
+				sb.WriteRune(char)
+				sb.WriteRune('r')
+			}
+			for _, d := range Decorations {
+				if style.Decoration(d) == True {
+					sb.WriteRune(char)
+					sb.WriteRune(d.legacyCode())
+				}
+			}
+			current = style
+		}
+		sb.WriteString(text)
+	})
+	return sb.String()
+}
+
+// legacyEqual reports whether s and other look the same with legacy codes.
+func (s Style) legacyEqual(other Style) bool {
+	if (s.Color == nil) != (other.Color == nil) {
+		return false
+	}
+	if s.Color != nil && s.Color.Nearest().code != other.Color.Nearest().code {
+		return false
+	}
+	for _, d := range Decorations {
+		if (s.Decoration(d) == True) != (other.Decoration(d) == True) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package component
+
+import "testing"
+
+func TestParseLegacy(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		char rune
+		json string
+	}{
+		{name: "no codes", text: "hello", char: SectionChar, json: `"hello"`},
+		{name: "color", text: "§cHello", char: SectionChar, json: `{"color":"red","text":"Hello"}`},
+		{name: "upper case code", text: "§CHello", char: SectionChar, json: `{"color":"red","text":"Hello"}`},
+		{name: "ampersand", text: "&aHi", char: '&', json: `{"color":"green","text":"Hi"}`},
+		{name: "decoration after color", text: "§c§lHi", char: SectionChar, json: `{"bold":true,"color":"red","text":"Hi"}`},
+		{
+			name: "color after decoration resets it",
+			text: "§lA§cB",
+			char: SectionChar,
+			json: `{"extra":[{"bold":true,"text":"A"},{"color":"red","text":"B"}],"text":""}`,
+		},
+		{
+			name: "decorations accumulate",
+			text: "§lA§oB",
+			char: SectionChar,
+			json: `{"extra":[{"bold":true,"text":"A"},{"bold":true,"italic":true,"text":"B"}],"text":""}`,
+		},
+		{
+			name: "reset",
+			text: "§c§nA§rB",
+			char: SectionChar,
+			json: `{"extra":[{"color":"red","text":"A","underlined":true},"B"],"text":""}`,
+		},
+		{name: "every decoration", text: "§k§l§m§n§oX", char: SectionChar, json: `{"bold":true,"italic":true,"obfuscated":true,"strikethrough":true,"text":"X","underlined":true}`},
+		{name: "hex color", text: "§x§F§f§0§0§8§0Hi", char: SectionChar, json: `{"color":"#FF0080","text":"Hi"}`},
+		{name: "incomplete hex color", text: "§x§F§fHi", char: SectionChar, json: `{"extra":["§x",{"color":"white","text":"Hi"}],"text":""}`},
+		{name: "unknown code is kept", text: "§zA", char: SectionChar, json: `"§zA"`},
+		{name: "trailing code character", text: "A§", char: SectionChar, json: `"A§"`},
+		{name: "other character is text", text: "§cA", char: '&', json: `"§cA"`},
+		{name: "empty", text: "", char: SectionChar, json: `""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseLegacy(tt.text, tt.char).JSON(); got != tt.json {
+				t.Errorf("got %s, want %s", got, tt.json)
+			}
+		})
+	}
+}
+
+func TestLegacy(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Component
+		want string
+	}{
+		{name: "plain", c: Text("hi"), want: "hi"},
+		{name: "color then decoration", c: Text("A").Color(Red).Decorate(Bold), want: "§c§lA"},
+		{name: "inherited style", c: Text("a").Color(Red).Append(Text("b").Decorate(Bold)), want: "§ca§c§lb"},
+		{name: "same style is not repeated", c: Text("a").Color(Red).Append(Text("b").Color(Red)), want: "§cab"},
+		{name: "change of color", c: Text("a").Color(Red).Append(Text("b").Color(Blue)), want: "§ca§9b"},
+		{name: "decoration switched off", c: Text("a").Decorate(Bold).Append(Text("b").Decoration(Bold, False)), want: "§la§rb"},
+		{name: "unstyled after styled", c: Empty().Append(Text("a").Color(Red), Text("b")), want: "§ca§rb"},
+		{name: "hex color becomes the nearest named color", c: Text("a").Color(Hex(0xFE5656)), want: "§ca"},
+		{name: "translatable shows its key", c: Translatable("chat.type.text").Color(Gray), want: "§7chat.type.text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Legacy(SectionChar); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			// Parsing the result must look the same
+			if got := ParseLegacy(tt.want, SectionChar).Legacy(SectionChar); got != tt.want {
+				t.Errorf("after parsing got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := Text("a").Color(Green).Legacy('&'); got != "&aa" {
+		t.Errorf("with '&' got %q, want %q", got, "&aa")
+	}
+}
@@ -0,0 +1,293 @@
+package component
+
+import (
+	"strings"
+)
+
+// ParseMiniMessage converts MiniMessage-style markup into a component, for example
+//
+//	<red>Hello <bold>world</bold></red> <click:run_command:/spawn><u>click me</u></click>
+//
+// Supported tags are the color names, <color:...> and <#RRGGBB>; bold (b), italic (i, em),
+// underlined (u), strikethrough (st) and obfuscated (obf), which take an optional :false;
+// click, hover (show_text only), insertion and font; the self-closing key, lang (tr),
+// selector, score, newline (br) and reset. Unknown tags and unmatched closing tags are kept
+// as text, and "\<" escapes a literal "<". Parsing never fails.
+func ParseMiniMessage(input string) Component {
+	p := &miniMessageParser{frames: []miniMessageFrame{{c: Empty()}}}
+
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			p.append(Text(text.String()))
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		if ch == '\\' && i+1 < len(input) && (input[i+1] == '<' || input[i+1] == '\\') {
+			text.WriteByte(input[i+1])
+			i++
+			continue
+		}
+		if ch != '<' {
+			text.WriteByte(ch)
+			continue
+		}
+
+		end := tagEnd(input, i+1)
+		if end < 0 {
+			text.WriteByte(ch)
+			continue
+		}
+		flush()
+		if !p.tag(input[i+1 : end]) {
+			text.WriteString(input[i : end+1])
+		}
+		i = end
+	}
+	flush()
+
+	for len(p.frames) > 1 {
+		p.pop()
+	}
+	root := p.frames[0].c
+	if len(root.Children) == 1 {
+		return root.Children[0]
+	}
+	return root
+}
+
+type miniMessageFrame struct {
+	name string
+	c    Component
+}
+
+type miniMessageParser struct {
+	frames []miniMessageFrame
+}
+
+func (p *miniMessageParser) append(c Component) {
+	top := &p.frames[len(p.frames)-1]
+	// Text around tags kept as text arrives in pieces
+	if n := len(top.c.Children); n > 0 && c.isPlainText() && top.c.Children[n-1].isPlainText() {
+		last := &top.c.Children[n-1]
+		last.Content = TextContent{Text: last.Content.(TextContent).Text + c.Content.(TextContent).Text}
+		return
+	}
+	top.c.Children = append(top.c.Children, c)
+}
+
+func (p *miniMessageParser) push(name string, style func(*Style)) {
+	c := Empty()
+	style(&c.Style)
+	p.frames = append(p.frames, miniMessageFrame{name: name, c: c})
+}
+
+func (p *miniMessageParser) pop() {
+	top := p.frames[len(p.frames)-1]
+	p.frames = p.frames[:len(p.frames)-1]
+	if len(top.c.Children) == 0 {
+		return
+	}
+	// Move leading plain text into the styled component itself to keep the tree shallow
+	if first := top.c.Children[0]; first.isPlainText() {
+		top.c.Content = first.Content
+		top.c.Children = top.c.Children[1:]
+	}
+	p.append(top.c)
+}
+
+// tag applies the tag between < and >, reporting false if it is not a known tag.
+func (p *miniMessageParser) tag(tag string) bool {
+	if strings.HasPrefix(tag, "/") {
+		return p.close(canonicalTag(strings.ToLower(tag[1:])))
+	}
+
+	args := splitTagArgs(tag)
+	name := strings.ToLower(args[0])
+	args = args[1:]
+
+	if color, err := ParseColor(name); err == nil {
+		p.push("color", func(s *Style) { s.Color = &color })
+		return true
+	}
+
+	switch canonicalTag(name) {
+	case "color":
+		if len(args) != 1 {
+			return false
+		}
+		color, err := ParseColor(strings.ToLower(args[0]))
+		if err != nil {
+			return false
+		}
+		p.push("color", func(s *Style) { s.Color = &color })
+	case "bold", "italic", "underlined", "strikethrough", "obfuscated":
+		d := decorationByName(canonicalTag(name))
+		state := True
+		if len(args) == 1 && args[0] == "false" {
+			state = False
+		}
+		p.push(canonicalTag(name), func(s *Style) { s.SetDecoration(d, state) })
+	case "click":
+		if len(args) < 2 {
+			return false
+		}
+		event := &ClickEvent{Action: ClickAction(strings.ToLower(args[0])), Value: strings.Join(args[1:], ":")}
+		p.push("click", func(s *Style) { s.ClickEvent = event })
+	case "hover":
+		if len(args) < 2 || strings.ToLower(args[0]) != string(ShowText) {
+			return false
+		}
+		event := ShowTextEvent(ParseMiniMessage(strings.Join(args[1:], ":")))
+		p.push("hover", func(s *Style) { s.HoverEvent = event })
+	case "insertion":
+		if len(args) == 0 {
+			return false
+		}
+		p.push("insertion", func(s *Style) { s.Insertion = strings.Join(args, ":") })
+	case "font":
+		if len(args) == 0 {
+			return false
+		}
+		p.push("font", func(s *Style) { s.Font = strings.Join(args, ":") })
+	case "key":
+		if len(args) == 0 {
+			return false
+		}
+		p.append(Keybind(strings.Join(args, ":")))
+	case "lang":
+		if len(args) < 1 {
+			return false
+		}
+		translateArgs := make([]Component, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			translateArgs = append(translateArgs, ParseMiniMessage(arg))
+		}
+		p.append(Translatable(args[0], translateArgs...))
+	case "selector":
+		if len(args) == 0 {
+			return false
+		}
+		p.append(Selector(strings.Join(args, ":")))
+	case "score":
+		if len(args) != 2 {
+			return false
+		}
+		p.append(Score(args[0], args[1]))
+	case "newline":
+		p.append(Text("\n"))
+	case "reset":
+		for len(p.frames) > 1 {
+			p.pop()
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// close closes the innermost open tag called name and every tag opened after it.
+func (p *miniMessageParser) close(name string) bool {
+	if _, err := ParseColor(name); err == nil {
+		name = "color"
+	}
+	for i := len(p.frames) - 1; i > 0; i-- {
+		if p.frames[i].name == name {
+			for len(p.frames) > i {
+				p.pop()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalTag maps tag aliases to their full name.
+func canonicalTag(name string) string {
+	switch name {
+	case "colour", "c":
+		return "color"
+	case "b":
+		return "bold"
+	case "i", "em":
+		return "italic"
+	case "u":
+		return "underlined"
+	case "st":
+		return "strikethrough"
+	case "obf":
+		return "obfuscated"
+	case "tr", "translate":
+		return "lang"
+	case "sel":
+		return "selector"
+	case "br":
+		return "newline"
+	}
+	return name
+}
+
+func decorationByName(name string) Decoration {
+	for _, d := range Decorations {
+		if d.String() == name {
+			return d
+		}
+	}
+	return Bold
+}
+
+// tagEnd returns the index of the '>' closing a tag that starts at start, skipping
+// quoted arguments, or -1 if the tag is not closed.
+func tagEnd(input string, start int) int {
+	var quote byte
+	for i := start; i < len(input); i++ {
+		switch ch := input[i]; {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '<':
+			return -1
+		case ch == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTagArgs splits a tag at colons outside quotes and unquotes the parts.
+func splitTagArgs(tag string) []string {
+	var args []string
+	var arg strings.Builder
+	var quote byte
+
+	for i := 0; i < len(tag); i++ {
+		ch := tag[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && i+1 < len(tag) {
+				i++
+				arg.WriteByte(tag[i])
+			} else if ch == quote {
+				quote = 0
+			} else {
+				arg.WriteByte(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == ':':
+			args = append(args, arg.String())
+			arg.Reset()
+		default:
+			arg.WriteByte(ch)
+		}
+	}
+	return append(args, arg.String())
+}
@@ -0,0 +1,74 @@
+package component
+
+import "testing"
+
+func TestParseMiniMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		json  string
+	}{
+		{name: "plain", input: "hello", json: `"hello"`},
+		{name: "color", input: "<red>Hello</red>", json: `{"color":"red","text":"Hello"}`},
+		{name: "upper case tag", input: "<RED>Hello</RED>", json: `{"color":"red","text":"Hello"}`},
+		{name: "hex color", input: "<#ff0080>Hi", json: `{"color":"#FF0080","text":"Hi"}`},
+		{name: "color tag", input: "<color:gold>Hi</color>", json: `{"color":"gold","text":"Hi"}`},
+		{name: "color alias", input: "<c:gold>Hi</c>", json: `{"color":"gold","text":"Hi"}`},
+		{name: "closing a color by name", input: "<color:gold>a</gold>b", json: `{"extra":[{"color":"gold","text":"a"},"b"],"text":""}`},
+		{
+			name:  "nested",
+			input: "<red>Hello <bold>world</bold></red>",
+			json:  `{"color":"red","extra":[{"bold":true,"text":"world"}],"text":"Hello "}`,
+		},
+		{name: "decoration aliases", input: "<b><i><u><st><obf>x", json: `{"bold":true,"extra":[{"extra":[{"extra":[{"extra":[{"obfuscated":true,"text":"x"}],"strikethrough":true,"text":""}],"text":"","underlined":true}],"italic":true,"text":""}],"text":""}`},
+		{name: "decoration switched off", input: "<bold:false>x", json: `{"bold":false,"text":"x"}`},
+		{name: "unclosed tags close at the end", input: "<bold>x", json: `{"bold":true,"text":"x"}`},
+		{
+			name:  "closing a tag closes the tags inside it",
+			input: "<red><b>a</red>b",
+			json:  `{"extra":[{"color":"red","extra":[{"bold":true,"text":"a"}],"text":""},"b"],"text":""}`,
+		},
+		{name: "reset", input: "<red><b>a<reset>b", json: `{"extra":[{"color":"red","extra":[{"bold":true,"text":"a"}],"text":""},"b"],"text":""}`},
+		{
+			name:  "click",
+			input: "<click:run_command:/tp 0 64 0>go</click>",
+			json:  `{"click_event":{"action":"run_command","command":"/tp 0 64 0"},"text":"go"}`,
+		},
+		{
+			name:  "click value with colons",
+			input: "<click:open_url:https://example.com>go",
+			json:  `{"click_event":{"action":"open_url","url":"https://example.com"},"text":"go"}`,
+		},
+		{
+			name:  "hover with quoted markup",
+			input: "<hover:show_text:'<red>tip'>x</hover>",
+			json:  `{"hover_event":{"action":"show_text","value":{"color":"red","text":"tip"}},"text":"x"}`,
+		},
+		{name: "insertion", input: "<insertion:hello>x", json: `{"insertion":"hello","text":"x"}`},
+		{name: "font", input: "<font:minecraft:uniform>x", json: `{"font":"minecraft:uniform","text":"x"}`},
+		{name: "key", input: "<key:key.jump>", json: `{"keybind":"key.jump"}`},
+		{
+			name:  "lang with arguments",
+			input: "<tr:chat.type.text:Steve:'<red>hi'>",
+			json:  `{"translate":"chat.type.text","with":["Steve",{"color":"red","text":"hi"}]}`,
+		},
+		{name: "selector", input: "<selector:@p>", json: `{"selector":"@p"}`},
+		{name: "score", input: "<score:@s:kills>", json: `{"score":{"name":"@s","objective":"kills"}}`},
+		{name: "newline", input: "a<br>b", json: `"a\nb"`},
+		{name: "unknown tag is text", input: "<foo>bar</foo>", json: `"<foo>bar</foo>"`},
+		{name: "tag missing arguments is text", input: "<click:run_command>x", json: `"<click:run_command>x"`},
+		{name: "unmatched closing tag is text", input: "a</red>b", json: `"a</red>b"`},
+		{name: "unclosed bracket is text", input: "a < b", json: `"a < b"`},
+		{name: "escaped tag", input: `\<red>x`, json: `"<red>x"`},
+		{name: "escaped backslash", input: `a\\<b>x`, json: `{"extra":["a\\",{"bold":true,"text":"x"}],"text":""}`},
+		{name: "lone backslash", input: `a\b`, json: `"a\\b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMiniMessage(tt.input).JSON(); got != tt.json {
+				t.Errorf("got %s, want %s", got, tt.json)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package component
+
+import (
+	"Veloce/internal/network/common"
+	"fmt"
+)
+
+// NBT encodes c in the network NBT format Play and Configuration packets use for text.
+func (c Component) NBT() common.Tag {
+	if c.isPlainText() {
+		return common.StringTag(c.Content.(TextContent).Text)
+	}
+	return toTag(c.encode(false))
+}
+
+// FromNBT decodes a component from network NBT.
+func FromNBT(tag common.Tag) (Component, error) {
+	return decode(fromTag(tag))
+}
+
+func toTag(v any) common.Tag {
+	switch v := v.(type) {
+	case string:
+		return common.StringTag(v)
+	case bool:
+		return common.BoolTag(v)
+	case int32:
+		return common.IntTag(v)
+	case []int32:
+		return common.IntArrayTag(v)
+	case []any:
+		list := common.NewListTag()
+		for _, elem := range v {
+			// encode only produces compound lists for NBT, so Add cannot fail
+			_ = list.Add(toTag(elem))
+		}
+		return list
+	case map[string]any:
+		compound := make(common.CompoundTag, len(v))
+		for key, elem := range v {
+			compound[key] = toTag(elem)
+		}
+		return compound
+	}
+	panic(fmt.Sprintf("component: cannot encode %T", v))
+}
+
+func fromTag(tag common.Tag) any {
+	switch tag := tag.(type) {
+	case common.StringTag:
+		return string(tag)
+	case common.ByteTag:
+		return float64(tag)
+	case common.ShortTag:
+		return float64(tag)
+	case common.IntTag:
+		return float64(tag)
+	case common.LongTag:
+		return float64(tag)
+	case common.FloatTag:
+		return float64(tag)
+	case common.DoubleTag:
+		return float64(tag)
+	case common.IntArrayTag:
+		list := make([]any, len(tag))
+		for i, v := range tag {
+			list[i] = float64(v)
+		}
+		return list
+	case *common.ListTag:
+		list := make([]any, len(tag.Elems))
+		for i, elem := range tag.Elems {
+			list[i] = fromTag(elem)
+		}
+		return list
+	case common.CompoundTag:
+		m := make(map[string]any, len(tag))
+		for key, elem := range tag {
+			m[key] = fromTag(elem)
+		}
+		return m
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package component
+
+import (
+	"Veloce/internal/network/common"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNBT(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Component
+		want common.Tag
+	}{
+		{name: "plain text is a string", c: Text("hi"), want: common.StringTag("hi")},
+		{
+			name: "children are never strings",
+			c:    Text("a").Append(Text("b")),
+			want: common.CompoundTag{
+				"text":  common.StringTag("a"),
+				"extra": common.NewListTag(common.CompoundTag{"text": common.StringTag("b")}),
+			},
+		},
+		{
+			name: "decorations are bytes",
+			c:    Text("a").Decorate(Bold).Decoration(Italic, False),
+			want: common.CompoundTag{
+				"text":   common.StringTag("a"),
+				"bold":   common.ByteTag(1),
+				"italic": common.ByteTag(0),
+			},
+		},
+		{
+			name: "click event",
+			c:    Text("a").Click(SuggestCommand, "/msg "),
+			want: common.CompoundTag{
+				"text": common.StringTag("a"),
+				"click_event": common.CompoundTag{
+					"action":  common.StringTag("suggest_command"),
+					"command": common.StringTag("/msg "),
+				},
+			},
+		},
+		{
+			name: "hover text is a compound",
+			c:    Text("a").Hover(ShowTextEvent(Text("tip"))),
+			want: common.CompoundTag{
+				"text": common.StringTag("a"),
+				"hover_event": common.CompoundTag{
+					"action": common.StringTag("show_text"),
+					"value":  common.CompoundTag{"text": common.StringTag("tip")},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.NBT(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromNBT(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  common.Tag
+		want Component
+	}{
+		{
+			name: "wrapped list elements",
+			tag: common.CompoundTag{
+				"text":  common.StringTag("a"),
+				"extra": common.NewListTag(common.CompoundTag{"": common.StringTag("b")}),
+			},
+			want: Text("a").Append(Text("b")),
+		},
+		{
+			name: "list of strings",
+			tag:  common.NewListTag(common.StringTag("a"), common.StringTag("b")),
+			want: Text("a").Append(Text("b")),
+		},
+		{
+			name: "decorations from any number",
+			tag:  common.CompoundTag{"text": common.StringTag("a"), "bold": common.IntTag(1), "italic": common.ShortTag(0)},
+			want: Text("a").Decorate(Bold).Decoration(Italic, False),
+		},
+		{
+			name: "numeric text",
+			tag:  common.CompoundTag{"text": common.IntTag(7)},
+			want: Text("7"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromNBT(tt.tag)
+			if err != nil {
+				t.Fatalf("FromNBT: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromNBTMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  common.Tag
+	}{
+		{name: "nil", tag: nil},
+		{name: "byte array", tag: common.ByteArrayTag{1, 2}},
+		{name: "long array", tag: common.LongArrayTag{1}},
+		{name: "empty list", tag: common.NewListTag()},
+		{name: "empty compound", tag: common.CompoundTag{}},
+		{name: "text of an unsupported type", tag: common.CompoundTag{"text": common.ByteArrayTag{}}},
+		{
+			name: "unsupported child",
+			tag: common.CompoundTag{
+				"text":  common.StringTag("a"),
+				"extra": common.NewListTag(common.LongArrayTag{1}),
+			},
+		},
+		{
+			name: "unsupported translation argument",
+			tag: common.CompoundTag{
+				"translate": common.StringTag("key"),
+				"with":      common.NewListTag(common.CompoundTag{}),
+			},
+		},
+		{
+			name: "unsupported hover text",
+			tag: common.CompoundTag{
+				"text": common.StringTag("a"),
+				"hover_event": common.CompoundTag{
+					"action": common.StringTag("show_text"),
+					"value":  common.ByteArrayTag{},
+				},
+			},
+		},
+		{
+			name: "unsupported entity name",
+			tag: common.CompoundTag{
+				"text": common.StringTag("a"),
+				"hover_event": common.CompoundTag{
+					"action": common.StringTag("show_entity"),
+					"name":   common.LongArrayTag{},
+				},
+			},
+		},
+		{name: "unknown color", tag: common.CompoundTag{"text": common.StringTag("a"), "color": common.StringTag("crimson")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := FromNBT(tt.tag)
+			if !errors.Is(err, ErrInvalidComponent) {
+				t.Errorf("got %#v with error %v, want %v", c, err, ErrInvalidComponent)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package component
+
+// TriState is a style property that is either set to true, set to false or inherited.
+type TriState byte
+
+const (
+	Unset TriState = iota
+	False
+	True
+)
+
+// StateOf returns the TriState set to v.
+func StateOf(v bool) TriState {
+	if v {
+		return True
+	}
+	return False
+}
+
+// Decoration is a text decoration that can be switched on or off.
+type Decoration byte
+
+const (
+	Bold Decoration = iota
+	Italic
+	Underlined
+	Strikethrough
+	Obfuscated
+)
+
+// Decorations lists every decoration in legacy code order.
+var Decorations = []Decoration{Obfuscated, Bold, Strikethrough, Underlined, Italic}
+
+// String returns the decoration's key in the JSON and NBT formats.
+func (d Decoration) String() string {
+	switch d {
+	case Bold:
+		return "bold"
+	case Italic:
+		return "italic"
+	case Underlined:
+		return "underlined"
+	case Strikethrough:
+		return "strikethrough"
+	case Obfuscated:
+		return "obfuscated"
+	}
+	return "unknown"
+}
+
+// legacyCode returns the decoration's legacy formatting code.
+func (d Decoration) legacyCode() rune {
+	return [...]rune{'l', 'o', 'n', 'm', 'k'}[d]
+}
+
+// Style is how a component and, unless they override it, its children are displayed.
+type Style struct {
+	Color         *TextColor
+	Bold          TriState
+	Italic        TriState
+	Underlined    TriState
+	Strikethrough TriState
+	Obfuscated    TriState
+	Font          string // Resource location of the font, empty for the default
+	Insertion     string // Text inserted into chat when shift-clicked
+	ClickEvent    *ClickEvent
+	HoverEvent    *HoverEvent
+}
+
+func (s *Style) decoration(d Decoration) *TriState {
+	switch d {
+	case Bold:
+		return &s.Bold
+	case Italic:
+		return &s.Italic
+	case Underlined:
+		return &s.Underlined
+	case Strikethrough:
+		return &s.Strikethrough
+	default:
+		return &s.Obfuscated
+	}
+}
+
+// Decoration returns the state of d.
+func (s Style) Decoration(d Decoration) TriState {
+	return *s.decoration(d)
+}
+
+// SetDecoration sets the state of d.
+func (s *Style) SetDecoration(d Decoration, state TriState) {
+	*s.decoration(d) = state
+}
+
+// IsEmpty reports whether the style sets nothing.
+func (s Style) IsEmpty() bool {
+	return s == Style{}
+}
+
+// Merge returns s with every unset property taken from parent.
+func (s Style) Merge(parent Style) Style {
+	if s.Color == nil {
+		s.Color = parent.Color
+	}
+	for _, d := range Decorations {
+		if s.Decoration(d) == Unset {
+			s.SetDecoration(d, parent.Decoration(d))
+		}
+	}
+	if s.Font == "" {
+		s.Font = parent.Font
+	}
+	if s.Insertion == "" {
+		s.Insertion = parent.Insertion
+	}
+	if s.ClickEvent == nil {
+		s.ClickEvent = parent.ClickEvent
+	}
+	if s.HoverEvent == nil {
+		s.HoverEvent = parent.HoverEvent
+	}
+	return s
+}
@@ -1,18 +1,11 @@
 package player
 
 import (
-	"Veloce/internal/objects/optional"
-	"github.com/google/uuid"
+	"Veloce/internal/network/common"
 )
 
-type Property struct {
-	Name      string
-	Value     string
-	Signature optional.Optional[string]
-}
-
-type GameProfile struct {
-	UUID       uuid.UUID
-	Name       string
-	Properties []Property
-}
+// Property and GameProfile live in common so connections can carry them during login.
+type (
+	Property    = common.Property
+	GameProfile = common.GameProfile
+)
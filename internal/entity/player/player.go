@@ -1,20 +1,23 @@
 package player
 
 import (
+	"Veloce/internal/component"
 	"Veloce/internal/network/common"
 	"Veloce/internal/objects/coordinate"
 	"github.com/google/uuid"
 	"sync"
+	"time"
 )
 
 type Player struct {
 	//Internals
 	remoteAddr  string
-	pc          common.PlayerConnection
+	pc          *common.PlayerConnection
 	gameProfile GameProfile
 	mu          sync.RWMutex
 
 	// Player Fields
+	entityID    int32
 	uuid        uuid.UUID
 	displayName string
 	gameMode    GameMode
@@ -22,6 +25,24 @@ type Player struct {
 	velocity    coordinate.Vector
 }
 
+// NewPlayer creates a player for a connection that finished logging in as profile.
+func NewPlayer(entityID int32, profile GameProfile, pc *common.PlayerConnection) *Player {
+	p := &Player{
+		pc:          pc,
+		gameProfile: profile,
+		entityID:    entityID,
+		uuid:        profile.UUID,
+		displayName: profile.Name,
+		gameMode:    Survival,
+		position:    *coordinate.PosZero,
+		velocity:    *coordinate.Zero,
+	}
+	if addr := pc.RemoteAddr(); addr != nil {
+		p.remoteAddr = addr.String()
+	}
+	return p
+}
+
 func (p *Player) SendPacket(packet common.ClientboundPacket) error {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -32,6 +53,41 @@ func (p *Player) SendPacket(packet common.ClientboundPacket) error {
 	return nil
 }
 
+// GetConnection returns the connection the player is playing on.
+func (p *Player) GetConnection() *common.PlayerConnection {
+	return p.pc
+}
+
+// GetRemoteAddr returns the address the player connected from.
+func (p *Player) GetRemoteAddr() string {
+	return p.remoteAddr
+}
+
+func (p *Player) GetGameProfile() GameProfile {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.gameProfile
+}
+
+// GetUsername returns the account name from the player's profile.
+func (p *Player) GetUsername() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.gameProfile.Name
+}
+
+func (p *Player) SetDisplayName(displayName string) {
+	p.mu.Lock()
+	p.displayName = displayName
+	p.mu.Unlock()
+}
+
+func (p *Player) GetDisplayName() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.displayName
+}
+
 func (p *Player) SetGameMode(gameMode GameMode) {
 	p.mu.Lock()
 	p.gameMode = gameMode
@@ -44,6 +100,18 @@ func (p *Player) GetGameMode() GameMode {
 	return p.gameMode
 }
 
+func (p *Player) SetPosition(position coordinate.Position) {
+	p.mu.Lock()
+	p.position = position
+	p.mu.Unlock()
+}
+
+func (p *Player) GetPosition() coordinate.Position {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.position
+}
+
 func (p *Player) SetVelocity(velocity coordinate.Vector) {
 	p.mu.Lock()
 	p.velocity = velocity
@@ -56,6 +124,21 @@ func (p *Player) GetVelocity() coordinate.Vector {
 	return p.velocity
 }
 
+// Kick disconnects the player, showing reason on the disconnect screen.
+func (p *Player) Kick(reason component.Component) error {
+	return p.pc.Disconnect(reason)
+}
+
+// GetLatency returns the player's round trip time, as shown in the tab list.
+func (p *Player) GetLatency() time.Duration {
+	return p.pc.GetLatency()
+}
+
+// GetEntityID returns the ID identifying the player's entity in packets.
+func (p *Player) GetEntityID() int32 {
+	return p.entityID
+}
+
 func (p *Player) GetUUID() uuid.UUID {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
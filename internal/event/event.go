@@ -17,5 +17,5 @@ type CancellableEvent interface {
 // PlayerEvent is an event associated with a specific player.
 type PlayerEvent interface {
     Event
-    GetPlayer() player.Player
+    GetPlayer() *player.Player
 }
\ No newline at end of file
@@ -2,18 +2,30 @@ package events
 
 import "Veloce/internal/network/common"
 
-// PacketIncomingEvent is for C -> S (C2S)
+// PacketIncomingEvent is for C -> S (C2S). It is called before the packet is handled;
+// cancelling it skips the handler and setting Packet handles a different packet instead.
 type PacketIncomingEvent struct {
-	Packet     *common.ServerboundPacket
+	Packet     common.ServerboundPacket
 	Connection *common.PlayerConnection
+	cancelled  bool
 }
 
 func (evt *PacketIncomingEvent) IsEvent() {}
 
-// PacketOutgoingEvent is for S -> C (S2C)
+func (evt *PacketIncomingEvent) IsCancelled() bool { return evt.cancelled }
+
+func (evt *PacketIncomingEvent) SetCancelled(isCancelled bool) { evt.cancelled = isCancelled }
+
+// PacketOutgoingEvent is for S -> C (S2C). It is called before the packet is encoded;
+// cancelling it drops the packet and setting Packet sends a different packet instead.
 type PacketOutgoingEvent struct {
-	Packet     *common.ClientboundPacket
+	Packet     common.ClientboundPacket
 	Connection *common.PlayerConnection
+	cancelled  bool
 }
 
-func (evt *PacketOutgoingEvent) IsEvent() {}
\ No newline at end of file
+func (evt *PacketOutgoingEvent) IsEvent() {}
+
+func (evt *PacketOutgoingEvent) IsCancelled() bool { return evt.cancelled }
+
+func (evt *PacketOutgoingEvent) SetCancelled(isCancelled bool) { evt.cancelled = isCancelled }
@@ -0,0 +1,21 @@
+package events
+
+import "Veloce/internal/entity/player"
+
+// PlayerJoinEvent is called once a player has entered the Play state and is online.
+type PlayerJoinEvent struct {
+	Player *player.Player
+}
+
+func (evt *PlayerJoinEvent) IsEvent() {}
+
+func (evt *PlayerJoinEvent) GetPlayer() *player.Player { return evt.Player }
+
+// PlayerQuitEvent is called after an online player disconnected and was removed.
+type PlayerQuitEvent struct {
+	Player *player.Player
+}
+
+func (evt *PlayerQuitEvent) IsEvent() {}
+
+func (evt *PlayerQuitEvent) GetPlayer() *player.Player { return evt.Player }
@@ -0,0 +1,21 @@
+package events
+
+import (
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+)
+
+// ServerListPingEvent is called when a client requests the server status. Response is
+// prefilled from the server and may be changed; cancelling it closes the connection
+// without an answer. The address the client connected to is available from the Connection.
+type ServerListPingEvent struct {
+	Connection *common.PlayerConnection
+	Response   *clientbound.StatusResponse
+	cancelled  bool
+}
+
+func (evt *ServerListPingEvent) IsEvent() {}
+
+func (evt *ServerListPingEvent) IsCancelled() bool { return evt.cancelled }
+
+func (evt *ServerListPingEvent) SetCancelled(isCancelled bool) { evt.cancelled = isCancelled }
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"sync"
+)
+
+// Authenticator holds the server's login security settings shared by every connection.
+type Authenticator struct {
+	onlineMode bool
+	keyPair    *KeyPair
+	verifier   SessionVerifier
+	mu         sync.RWMutex
+}
+
+// NewAuthenticator creates an authenticator in offline mode that verifies against Mojang
+// once online mode is enabled.
+func NewAuthenticator() *Authenticator {
+	return &Authenticator{
+		verifier: NewMojangSessionVerifier(),
+	}
+}
+
+// SetOnlineMode enables or disables encryption and session verification for new logins.
+func (a *Authenticator) SetOnlineMode(online bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onlineMode = online
+}
+
+// IsOnlineMode reports whether new logins are encrypted and verified.
+func (a *Authenticator) IsOnlineMode() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.onlineMode
+}
+
+// SetSessionVerifier replaces the service used to verify joins.
+func (a *Authenticator) SetSessionVerifier(verifier SessionVerifier) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.verifier = verifier
+}
+
+// GetSessionVerifier returns the service used to verify joins.
+func (a *Authenticator) GetSessionVerifier() SessionVerifier {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.verifier
+}
+
+// KeyPair returns the login key pair, generating it on first use.
+func (a *Authenticator) KeyPair() (*KeyPair, error) {
+	a.mu.RLock()
+	keyPair := a.keyPair
+	a.mu.RUnlock()
+	if keyPair != nil {
+		return keyPair, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.keyPair == nil {
+		generated, err := NewKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		a.keyPair = generated
+	}
+	return a.keyPair, nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// keyBits is the RSA key size vanilla uses for the login key pair.
+	keyBits = 1024
+	// VerifyTokenLength is the length of the token echoed back by the client.
+	VerifyTokenLength = 4
+	// SharedSecretLength is the AES key length chosen by the client.
+	SharedSecretLength = 16
+)
+
+// KeyPair is the RSA key pair used to exchange the shared secret during login.
+type KeyPair struct {
+	private   *rsa.PrivateKey
+	publicDER []byte
+}
+
+// NewKeyPair generates a fresh login key pair.
+func NewKeyPair() (*KeyPair, error) {
+	private, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generating key pair: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("encoding public key: %w", err)
+	}
+	return &KeyPair{private: private, publicDER: publicDER}, nil
+}
+
+// PublicKey returns the ASN.1 DER encoded public key sent in the Encryption Request.
+func (k *KeyPair) PublicKey() []byte {
+	return k.publicDER
+}
+
+// Decrypt decrypts a PKCS#1 v1.5 block encrypted by the client with our public key.
+func (k *KeyPair) Decrypt(data []byte) ([]byte, error) {
+	return rsa.DecryptPKCS1v15(nil, k.private, data)
+}
+
+// NewVerifyToken returns a random verify token.
+func NewVerifyToken() ([]byte, error) {
+	token := make([]byte, VerifyTokenLength)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ServerHash computes the server ID hash sent to the session server: a SHA-1 digest of the
+// server ID, shared secret and public key, printed as a signed hexadecimal number.
+func ServerHash(serverID string, sharedSecret, publicKey []byte) string {
+	h := sha1.New()
+	h.Write([]byte(serverID))
+	h.Write(sharedSecret)
+	h.Write(publicKey)
+	digest := h.Sum(nil)
+
+	negative := digest[0]&0x80 != 0
+	if negative {
+		// Two's complement, so the magnitude can be printed with a minus sign
+		carry := true
+		for i := len(digest) - 1; i >= 0; i-- {
+			digest[i] = ^digest[i]
+			if carry {
+				digest[i]++
+				carry = digest[i] == 0
+			}
+		}
+	}
+
+	hash := new(big.Int).SetBytes(digest).Text(16)
+	if negative {
+		return "-" + hash
+	}
+	return hash
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"Veloce/internal/network/common"
+	"crypto/md5"
+	"errors"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyUsername   = errors.New("username is empty")
+	ErrUsernameTooLong = errors.New("username is longer than 16 characters")
+	ErrInvalidUsername = errors.New("username contains invalid characters")
+)
+
+// ValidateUsername applies vanilla's player name rules: 1 to 16 printable ASCII characters
+// without spaces.
+func ValidateUsername(name string) error {
+	if name == "" {
+		return ErrEmptyUsername
+	}
+	if len(name) > common.MaxProfileNameLength {
+		return ErrUsernameTooLong
+	}
+	for i := 0; i < len(name); i++ {
+		if name[i] <= ' ' || name[i] >= 0x7F {
+			return ErrInvalidUsername
+		}
+	}
+	return nil
+}
+
+// OfflineUUID derives the UUID vanilla assigns to name in offline mode,
+// a version 3 UUID of "OfflinePlayer:<name>" (Java's UUID.nameUUIDFromBytes).
+func OfflineUUID(name string) uuid.UUID {
+	hash := md5.Sum([]byte("OfflinePlayer:" + name))
+	hash[6] = hash[6]&0x0f | 0x30 // version 3
+	hash[8] = hash[8]&0x3f | 0x80 // IETF variant
+	return hash
+}
+
+// OfflineProfile builds the profile of an unauthenticated player.
+func OfflineProfile(name string) common.GameProfile {
+	return common.GameProfile{
+		UUID: OfflineUUID(name),
+		Name: name,
+	}
+}
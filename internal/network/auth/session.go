@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"Veloce/internal/network/common"
+	"Veloce/internal/objects/optional"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultSessionServer is Mojang's hasJoined endpoint.
+const DefaultSessionServer = "https://sessionserver.mojang.com/session/minecraft/hasJoined"
+
+// ErrNotAuthenticated is returned when the session server does not know about the join.
+var ErrNotAuthenticated = errors.New("failed to verify username")
+
+// SessionVerifier confirms that a client has joined the server through its session service.
+type SessionVerifier interface {
+	// HasJoined returns the authenticated profile for username, or ErrNotAuthenticated.
+	// ip is the client address, or empty to skip the address check.
+	HasJoined(ctx context.Context, username, serverHash, ip string) (common.GameProfile, error)
+}
+
+// HTTPSessionVerifier queries a hasJoined endpoint compatible with Mojang's session server.
+type HTTPSessionVerifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewMojangSessionVerifier creates a verifier for Mojang's session server.
+func NewMojangSessionVerifier() *HTTPSessionVerifier {
+	return &HTTPSessionVerifier{
+		URL:    DefaultSessionServer,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type hasJoinedResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties []struct {
+		Name      string `json:"name"`
+		Value     string `json:"value"`
+		Signature string `json:"signature"`
+	} `json:"properties"`
+}
+
+func (v *HTTPSessionVerifier) HasJoined(ctx context.Context, username, serverHash, ip string) (common.GameProfile, error) {
+	query := url.Values{}
+	query.Set("username", username)
+	query.Set("serverId", serverHash)
+	if ip != "" {
+		query.Set("ip", ip)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.URL+"?"+query.Encode(), nil)
+	if err != nil {
+		return common.GameProfile{}, err
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return common.GameProfile{}, fmt.Errorf("contacting session server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return common.GameProfile{}, ErrNotAuthenticated
+	default:
+		return common.GameProfile{}, fmt.Errorf("session server returned %s", resp.Status)
+	}
+
+	var body hasJoinedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return common.GameProfile{}, fmt.Errorf("decoding session server response: %w", err)
+	}
+
+	id, err := uuid.Parse(body.ID)
+	if err != nil {
+		return common.GameProfile{}, fmt.Errorf("session server returned invalid UUID %q: %w", body.ID, err)
+	}
+
+	profile := common.GameProfile{UUID: id, Name: body.Name}
+	for _, p := range body.Properties {
+		property := common.Property{Name: p.Name, Value: p.Value}
+		if p.Signature != "" {
+			property.Signature = *optional.Of(p.Signature)
+		}
+		profile.Properties = append(profile.Properties, property)
+	}
+	return profile, nil
+}
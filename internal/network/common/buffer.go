@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"github.com/google/uuid"
+	"io"
+	"unicode/utf16"
 )
 
 // Common errors returned by Buffer operations
@@ -14,6 +16,9 @@ var (
 )
 
 const (
+	// MaxStringLength is the longest string, in UTF-16 units, the protocol allows.
+	MaxStringLength = 32767
+
 	maxVarIntBytes  = 5
 	maxVarLongBytes = 10
 	uuidByteLength  = 16
@@ -106,8 +111,15 @@ func (b *Buffer) WriteString(s string) error {
 	return err
 }
 
-// ReadString reads a UTF-8 string with variable-length prefix encoding.
+// ReadString reads a UTF-8 string with variable-length prefix encoding, of at most
+// MaxStringLength UTF-16 units.
 func (b *Buffer) ReadString() (string, error) {
+	return b.ReadStringMax(MaxStringLength)
+}
+
+// ReadStringMax reads a string of at most maxLength UTF-16 units, which is how vanilla
+// limits every string field.
+func (b *Buffer) ReadStringMax(maxLength int) (string, error) {
 	length, err := b.ReadVarInt()
 	if err != nil {
 		return "", err
@@ -115,13 +127,28 @@ func (b *Buffer) ReadString() (string, error) {
 	if length < 0 {
 		return "", ErrNegativeLength
 	}
+	// A UTF-16 unit takes at most three bytes in UTF-8
+	if int(length) > maxLength*3 {
+		return "", ErrValueTooLarge
+	}
+	if int(length) > b.Len() {
+		return "", io.ErrUnexpectedEOF
+	}
 
-	data := make([]byte, length)
-	_, err = b.Read(data)
-	if err != nil {
-		return "", err
+	s := string(b.Next(int(length)))
+	if utf16Length(s) > maxLength {
+		return "", ErrValueTooLarge
 	}
-	return string(data), nil
+	return s, nil
+}
+
+// utf16Length counts the UTF-16 units of s. Invalid UTF-8 counts as U+FFFD.
+func utf16Length(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
 }
 
 // WriteInt16 writes a 16-bit signed integer in big-endian format.
@@ -274,13 +301,99 @@ func (b *Buffer) WriteUUID(u uuid.UUID) error {
 
 // ReadUUID reads a UUID from exactly 16 bytes in binary representation.
 func (b *Buffer) ReadUUID() (uuid.UUID, error) {
-	data := make([]byte, uuidByteLength)
-	_, err := b.Read(data)
-	if err != nil {
+	var u uuid.UUID
+	if _, err := io.ReadFull(b, u[:]); err != nil {
 		return uuid.UUID{}, err
 	}
-
-	var u uuid.UUID
-	copy(u[:], data)
 	return u, nil
 }
+
+// WriteNBT writes a tag in the nameless-root network NBT format. A nil tag is written as TAG_End.
+func (b *Buffer) WriteNBT(tag Tag) error {
+	return NewNBTWriter(b).WriteNetwork(tag)
+}
+
+// ReadNBT reads a nameless-root network NBT tag. TAG_End yields a nil tag.
+func (b *Buffer) ReadNBT() (Tag, error) {
+	return NewNBTReader(b, MaxNetworkNBTBytes).ReadNetwork()
+}
+
+// WriteNBTValue marshals v with MarshalNBT and writes it as network NBT.
+func (b *Buffer) WriteNBTValue(v any) error {
+	tag, err := MarshalNBT(v)
+	if err != nil {
+		return err
+	}
+	return b.WriteNBT(tag)
+}
+
+// ReadNBTValue reads network NBT and unmarshals it into the value pointed to by v.
+func (b *Buffer) ReadNBTValue(v any) error {
+	tag, err := b.ReadNBT()
+	if err != nil {
+		return err
+	}
+	return UnmarshalNBT(tag, v)
+}
+
+// WriteByteArray writes a byte array prefixed with its VarInt length.
+func (b *Buffer) WriteByteArray(data []byte) error {
+	if err := b.WriteVarInt(int32(len(data))); err != nil {
+		return err
+	}
+	_, err := b.Write(data)
+	return err
+}
+
+// ReadByteArray reads a VarInt length-prefixed byte array of at most maxLength bytes.
+func (b *Buffer) ReadByteArray(maxLength int) ([]byte, error) {
+	length, err := b.ReadArrayLength(maxLength)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(b, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadArrayLength reads the VarInt length of an array of at most maxLength elements. Every
+// element takes at least one byte, so lengths beyond the remaining data are rejected before
+// the caller allocates anything.
+func (b *Buffer) ReadArrayLength(maxLength int) (int, error) {
+	length, err := b.ReadVarInt()
+	if err != nil {
+		return 0, err
+	}
+	if length < 0 {
+		return 0, ErrNegativeLength
+	}
+	if int(length) > maxLength {
+		return 0, ErrValueTooLarge
+	}
+	if int(length) > b.Len() {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return int(length), nil
+}
+
+// ReadPrefixedArray reads a VarInt length-prefixed array of at most maxLength elements,
+// each decoded by read.
+func ReadPrefixedArray[T any](b *Buffer, maxLength int, read func(b *Buffer) (T, error)) ([]T, error) {
+	length, err := b.ReadArrayLength(maxLength)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, 0, length)
+	for i := 0; i < length; i++ {
+		value, err := read(b)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"runtime"
+	"testing"
+)
+
+// Decoding may allocate a fixed amount plus this much per input byte. Anything more means a
+// length read from the input was trusted before the data behind it was.
+const (
+	allocBase        = 64 * 1024
+	allocPerByteRead = 1024
+)
+
+// checkAlloc runs read on data and fails if it panics or allocates out of proportion to
+// the input.
+func checkAlloc(t *testing.T, data []byte, read func(b *Buffer)) {
+	t.Helper()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	read(NewBuffer(append([]byte(nil), data...)))
+	runtime.ReadMemStats(&after)
+
+	limit := uint64(allocBase + allocPerByteRead*len(data))
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > limit {
+		t.Fatalf("decoding %d bytes allocated %d bytes, limit %d", len(data), allocated, limit)
+	}
+}
+
+func FuzzReadStringMax(f *testing.F) {
+	f.Add([]byte{0x05, 'h', 'e', 'l', 'l', 'o'}, uint16(16))
+	f.Add([]byte{0x06, 0xE2, 0x82, 0xAC, 0xE2, 0x82, 0xAC}, uint16(1))
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x07}, uint16(MaxStringLength))
+	f.Add([]byte{0xFF, 0xFF, 0x7F}, uint16(MaxStringLength))
+
+	f.Fuzz(func(t *testing.T, data []byte, maxLength uint16) {
+		checkAlloc(t, data, func(b *Buffer) {
+			s, err := b.ReadStringMax(int(maxLength))
+			if err == nil && utf16Length(s) > int(maxLength) {
+				t.Fatalf("read %d UTF-16 units, limit %d", utf16Length(s), maxLength)
+			}
+		})
+	})
+}
+
+func FuzzReadUUID(f *testing.F) {
+	f.Add(make([]byte, 16))
+	f.Add(make([]byte, 15))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkAlloc(t, data, func(b *Buffer) {
+			if _, err := b.ReadUUID(); err == nil && len(data) < 16 {
+				t.Fatalf("read a UUID from %d bytes", len(data))
+			}
+		})
+	})
+}
+
+func FuzzReadByteArray(f *testing.F) {
+	f.Add([]byte{0x03, 1, 2, 3}, uint16(16))
+	f.Add([]byte{0x80, 0x80, 0x80, 0x80, 0x07}, uint16(MaxStringLength))
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F}, uint16(MaxStringLength))
+
+	f.Fuzz(func(t *testing.T, data []byte, maxLength uint16) {
+		checkAlloc(t, data, func(b *Buffer) {
+			array, err := b.ReadByteArray(int(maxLength))
+			if err == nil && len(array) > int(maxLength) {
+				t.Fatalf("read %d bytes, limit %d", len(array), maxLength)
+			}
+		})
+	})
+}
+
+func FuzzReadPrefixedArray(f *testing.F) {
+	f.Add([]byte{0x02, 0x01, 0x02}, uint16(16))
+	f.Add([]byte{0x80, 0x80, 0x80, 0x80, 0x07}, uint16(1<<15))
+	f.Add([]byte{0x05, 0x80}, uint16(16))
+
+	f.Fuzz(func(t *testing.T, data []byte, maxLength uint16) {
+		checkAlloc(t, data, func(b *Buffer) {
+			length, err := NewBuffer(b.Bytes()).ReadArrayLength(int(maxLength))
+			if err == nil && length > int(maxLength) {
+				t.Fatalf("accepted length %d, limit %d", length, maxLength)
+			}
+
+			values, err := ReadPrefixedArray(b, int(maxLength), (*Buffer).ReadVarInt)
+			if err == nil && len(values) > int(maxLength) {
+				t.Fatalf("read %d elements, limit %d", len(values), maxLength)
+			}
+		})
+	})
+}
+
+func FuzzReadGameProfile(f *testing.F) {
+	valid := NewBuffer(nil)
+	_ = valid.WriteGameProfile(GameProfile{
+		Name:       "Notch",
+		Properties: []Property{{Name: "textures", Value: "e30="}},
+	})
+	f.Add(valid.Bytes())
+	f.Add(append(make([]byte, 16), 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0x07))
+	f.Add(append(make([]byte, 16), 0x00, 0x10, 0xFF, 0xFF, 0x7F))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkAlloc(t, data, func(b *Buffer) {
+			profile, err := b.ReadGameProfile()
+			if err != nil {
+				return
+			}
+			if utf16Length(profile.Name) > MaxProfileNameLength {
+				t.Fatalf("name %q is too long", profile.Name)
+			}
+			if len(profile.Properties) > MaxProfileProperties {
+				t.Fatalf("read %d properties", len(profile.Properties))
+			}
+		})
+	})
+}
+
+func FuzzReadNBT(f *testing.F) {
+	valid := NewBuffer(nil)
+	_ = valid.WriteNBT(CompoundTag{
+		"name":  StringTag("Steve"),
+		"pos":   &ListTag{ElemType: TagDouble, Elems: []Tag{DoubleTag(1), DoubleTag(2), DoubleTag(3)}},
+		"ids":   IntArrayTag{1, 2, 3},
+		"bytes": ByteArrayTag{4, 5},
+	})
+	f.Add(valid.Bytes())
+	f.Add([]byte{0x09, 0x0a, 0x00, 0x1f, 0x00, 0x00})
+	f.Add([]byte{0x07, 0x00, 0x20, 0x00, 0x00})
+	f.Add([]byte{0x0c, 0x7f, 0x00, 0x00, 0x00})
+	f.Add([]byte{0x09, 0x09, 0x00, 0x00, 0x00, 0x01, 0x09, 0x00, 0x00, 0x00, 0x01})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkAlloc(t, data, func(b *Buffer) {
+			tag, err := b.ReadNBT()
+			if err != nil {
+				return
+			}
+			// Whatever was accepted must survive being written and read again
+			out := NewBuffer(nil)
+			if err := out.WriteNBT(tag); err != nil {
+				t.Fatalf("writing decoded tag: %v", err)
+			}
+			if _, err := out.ReadNBT(); err != nil {
+				t.Fatalf("reading re-encoded tag: %v", err)
+			}
+		})
+	})
+}
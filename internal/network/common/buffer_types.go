@@ -0,0 +1,380 @@
+package common
+
+import (
+	"Veloce/internal/objects/coordinate"
+	"Veloce/internal/objects/identifier"
+	"Veloce/internal/objects/optional"
+	"fmt"
+	"math"
+)
+
+// WriteBlockPosition writes the block containing p as a packed Position: x and z in 26 bits,
+// y in 12 bits.
+func (b *Buffer) WriteBlockPosition(p coordinate.Point) error {
+	x, y, z := int64(p.BlockX()), int64(p.BlockY()), int64(p.BlockZ())
+	return b.WriteInt64((x&0x3FFFFFF)<<38 | (z&0x3FFFFFF)<<12 | y&0xFFF)
+}
+
+// ReadBlockPosition reads a packed Position as the vector of its block coordinates.
+func (b *Buffer) ReadBlockPosition() (*coordinate.Vector, error) {
+	v, err := b.ReadInt64()
+	if err != nil {
+		return nil, err
+	}
+	x := v >> 38
+	y := v << 52 >> 52
+	z := v << 26 >> 38
+	return coordinate.NewVector(float64(x), float64(y), float64(z)), nil
+}
+
+// WriteAngle writes an angle in degrees as steps of 1/256 of a full turn.
+func (b *Buffer) WriteAngle(degrees float32) error {
+	return b.WriteByte(byte(int32(math.Floor(float64(degrees) * 256 / 360))))
+}
+
+// ReadAngle reads an angle written by WriteAngle, in degrees between -180 and 180.
+func (b *Buffer) ReadAngle() (float32, error) {
+	v, err := b.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	return float32(int8(v)) * 360 / 256, nil
+}
+
+// BitSet is a set of bits stored in 64-bit words, bit i being bit i%64 of word i/64 as in
+// Java's BitSet.
+type BitSet []uint64
+
+// NewBitSet returns a BitSet with room for n bits.
+func NewBitSet(n int) BitSet {
+	return make(BitSet, (n+63)/64)
+}
+
+// Get reports whether bit i is set.
+func (s BitSet) Get(i int) bool {
+	word := i / 64
+	return word < len(s) && s[word]&(1<<(i%64)) != 0
+}
+
+// Set sets bit i, which must be below the capacity the set was created with.
+func (s BitSet) Set(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+// Clear clears bit i.
+func (s BitSet) Clear(i int) {
+	if word := i / 64; word < len(s) {
+		s[word] &^= 1 << (i % 64)
+	}
+}
+
+// WriteBitSet writes a BitSet prefixed with its VarInt length in words.
+func (b *Buffer) WriteBitSet(s BitSet) error {
+	if err := b.WriteVarInt(int32(len(s))); err != nil {
+		return err
+	}
+	for _, word := range s {
+		if err := b.WriteUint64(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBitSet reads a length-prefixed BitSet of at most maxWords words.
+func (b *Buffer) ReadBitSet(maxWords int) (BitSet, error) {
+	return ReadPrefixedArray(b, maxWords, (*Buffer).ReadUint64)
+}
+
+// WriteFixedBitSet writes the first n bits of s in ceil(n/8) bytes, lowest bits first.
+func (b *Buffer) WriteFixedBitSet(s BitSet, n int) error {
+	for i := 0; i < (n+7)/8; i++ {
+		var v byte
+		if word := i / 8; word < len(s) {
+			v = byte(s[word] >> (8 * (i % 8)))
+		}
+		if err := b.WriteByte(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFixedBitSet reads a BitSet of n bits written by WriteFixedBitSet.
+func (b *Buffer) ReadFixedBitSet(n int) (BitSet, error) {
+	s := NewBitSet(n)
+	for i := 0; i < (n+7)/8; i++ {
+		v, err := b.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		s[i/8] |= uint64(v) << (8 * (i % 8))
+	}
+	return s, nil
+}
+
+// WriteOptional writes whether value is present and, if it is, the value itself.
+func WriteOptional[T any](b *Buffer, value optional.Optional[T], write func(b *Buffer, v T) error) error {
+	if err := b.WriteBool(value.IsPresent()); err != nil {
+		return err
+	}
+	if value.IsPresent() {
+		return write(b, value.Get())
+	}
+	return nil
+}
+
+// ReadOptional reads a value written by WriteOptional.
+func ReadOptional[T any](b *Buffer, read func(b *Buffer) (T, error)) (optional.Optional[T], error) {
+	present, err := b.ReadBool()
+	if err != nil || !present {
+		return optional.Optional[T]{}, err
+	}
+	value, err := read(b)
+	if err != nil {
+		return optional.Optional[T]{}, err
+	}
+	return *optional.Of(value), nil
+}
+
+// WritePrefixedArray writes values prefixed with their VarInt count.
+func WritePrefixedArray[T any](b *Buffer, values []T, write func(b *Buffer, v T) error) error {
+	if err := b.WriteVarInt(int32(len(values))); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if err := write(b, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IDOr is the protocol's "ID or X": a reference into a registry, or a value sent inline.
+type IDOr[T any] struct {
+	ID    int32 // Registry ID, used when Value is nil
+	Value *T
+}
+
+// WriteIDOr writes the registry ID plus one, or zero followed by the inline value.
+func WriteIDOr[T any](b *Buffer, v IDOr[T], write func(b *Buffer, v T) error) error {
+	if v.Value == nil {
+		return b.WriteVarInt(v.ID + 1)
+	}
+	if err := b.WriteVarInt(0); err != nil {
+		return err
+	}
+	return write(b, *v.Value)
+}
+
+// ReadIDOr reads a value written by WriteIDOr.
+func ReadIDOr[T any](b *Buffer, read func(b *Buffer) (T, error)) (IDOr[T], error) {
+	id, err := b.ReadVarInt()
+	if err != nil {
+		return IDOr[T]{}, err
+	}
+	if id != 0 {
+		return IDOr[T]{ID: id - 1}, nil
+	}
+	value, err := read(b)
+	if err != nil {
+		return IDOr[T]{}, err
+	}
+	return IDOr[T]{Value: &value}, nil
+}
+
+// IDSet is a set of registry entries, given either as a tag or as a list of IDs.
+type IDSet struct {
+	Tag *identifier.Identifier // Used instead of IDs when not nil
+	IDs []int32
+}
+
+// WriteIDSet writes the set as zero and a tag name, or as the ID count plus one and the IDs.
+func (b *Buffer) WriteIDSet(set IDSet) error {
+	if set.Tag != nil {
+		if err := b.WriteVarInt(0); err != nil {
+			return err
+		}
+		return b.WriteIdentifier(*set.Tag)
+	}
+
+	if err := b.WriteVarInt(int32(len(set.IDs)) + 1); err != nil {
+		return err
+	}
+	for _, id := range set.IDs {
+		if err := b.WriteVarInt(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadIDSet reads an ID set of at most maxIDs IDs.
+func (b *Buffer) ReadIDSet(maxIDs int) (IDSet, error) {
+	kind, err := b.ReadVarInt()
+	if err != nil {
+		return IDSet{}, err
+	}
+	if kind == 0 {
+		tag, err := b.ReadIdentifier()
+		if err != nil {
+			return IDSet{}, err
+		}
+		return IDSet{Tag: tag}, nil
+	}
+
+	count := int(kind) - 1
+	if count < 0 {
+		return IDSet{}, ErrNegativeLength
+	}
+	if count > maxIDs || count > b.Len() {
+		return IDSet{}, ErrValueTooLarge
+	}
+	ids := make([]int32, count)
+	for i := range ids {
+		if ids[i], err = b.ReadVarInt(); err != nil {
+			return IDSet{}, err
+		}
+	}
+	return IDSet{IDs: ids}, nil
+}
+
+const (
+	lpVec3MaxComponent = 1.7179869183e10      // Larger components are clamped
+	lpVec3Epsilon      = 3.051944088384301e-5 // Smaller vectors are sent as zero
+	lpVec3Steps        = 32766.0
+)
+
+// WriteLpVec3 writes a low-precision vector, as used for entity velocities: a shared scale
+// and each component in 15 bits, or a single zero byte for vectors close to zero.
+func (b *Buffer) WriteLpVec3(v coordinate.Point) error {
+	x, y, z := lpVec3Sanitize(v.X()), lpVec3Sanitize(v.Y()), lpVec3Sanitize(v.Z())
+	maxAbs := math.Max(math.Abs(x), math.Max(math.Abs(y), math.Abs(z)))
+	if maxAbs < lpVec3Epsilon {
+		return b.WriteByte(0)
+	}
+
+	scale := int64(math.Ceil(maxAbs))
+	continued := scale&3 != scale
+	markers := scale
+	if continued {
+		markers = scale&3 | 4
+	}
+	packed := markers |
+		lpVec3Pack(x/float64(scale))<<3 |
+		lpVec3Pack(y/float64(scale))<<18 |
+		lpVec3Pack(z/float64(scale))<<33
+
+	if err := b.WriteByte(byte(packed)); err != nil {
+		return err
+	}
+	if err := b.WriteByte(byte(packed >> 8)); err != nil {
+		return err
+	}
+	if err := b.WriteUint32(uint32(packed >> 16)); err != nil {
+		return err
+	}
+	if continued {
+		return b.WriteVarInt(int32(scale >> 2))
+	}
+	return nil
+}
+
+// ReadLpVec3 reads a vector written by WriteLpVec3.
+func (b *Buffer) ReadLpVec3() (*coordinate.Vector, error) {
+	first, err := b.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if first == 0 {
+		return coordinate.NewVector(0, 0, 0), nil
+	}
+	second, err := b.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	rest, err := b.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	packed := int64(rest)<<16 | int64(second)<<8 | int64(first)
+
+	scale := int64(first & 3)
+	if first&4 != 0 {
+		high, err := b.ReadVarInt()
+		if err != nil {
+			return nil, err
+		}
+		scale |= int64(uint32(high)) << 2
+	}
+	s := float64(scale)
+	return coordinate.NewVector(
+		lpVec3Unpack(packed>>3)*s,
+		lpVec3Unpack(packed>>18)*s,
+		lpVec3Unpack(packed>>33)*s,
+	), nil
+}
+
+func lpVec3Sanitize(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(-lpVec3MaxComponent, math.Min(v, lpVec3MaxComponent))
+}
+
+func lpVec3Pack(v float64) int64 {
+	return int64(math.Round((v*0.5 + 0.5) * lpVec3Steps))
+}
+
+func lpVec3Unpack(v int64) float64 {
+	return math.Min(float64(v&0x7FFF), lpVec3Steps)*2/lpVec3Steps - 1
+}
+
+// WriteIdentifier writes a namespaced identifier as a string.
+func (b *Buffer) WriteIdentifier(id identifier.Identifier) error {
+	return b.WriteString(id.String())
+}
+
+// ReadIdentifier reads and validates a namespaced identifier.
+func (b *Buffer) ReadIdentifier() (*identifier.Identifier, error) {
+	s, err := b.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	id, err := identifier.ParseIdentifier(s)
+	if err != nil {
+		return nil, fmt.Errorf("identifier %q: %w", s, err)
+	}
+	return id, nil
+}
+
+// TeleportFlags marks which values of a teleport are relative to the current ones.
+type TeleportFlags int32
+
+const (
+	TeleportRelativeX TeleportFlags = 1 << iota
+	TeleportRelativeY
+	TeleportRelativeZ
+	TeleportRelativeYaw
+	TeleportRelativePitch
+	TeleportRelativeVelocityX
+	TeleportRelativeVelocityY
+	TeleportRelativeVelocityZ
+	TeleportRotateVelocity // Rotate the velocity by the change in rotation before applying it
+)
+
+// Has reports whether every flag in f is set.
+func (t TeleportFlags) Has(f TeleportFlags) bool {
+	return t&f == f
+}
+
+// WriteTeleportFlags writes teleport flags as a 32-bit field.
+func (b *Buffer) WriteTeleportFlags(flags TeleportFlags) error {
+	return b.WriteInt32(int32(flags))
+}
+
+// ReadTeleportFlags reads teleport flags written by WriteTeleportFlags.
+func (b *Buffer) ReadTeleportFlags() (TeleportFlags, error) {
+	v, err := b.ReadInt32()
+	return TeleportFlags(v), err
+}
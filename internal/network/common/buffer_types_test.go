@@ -0,0 +1,408 @@
+package common
+
+import (
+	"Veloce/internal/objects/coordinate"
+	"Veloce/internal/objects/identifier"
+	"Veloce/internal/objects/optional"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func bitSetOf(n int, bits ...int) BitSet {
+	s := NewBitSet(n)
+	for _, i := range bits {
+		s.Set(i)
+	}
+	return s
+}
+
+func writeString(b *Buffer, s string) error {
+	return b.WriteString(s)
+}
+
+func TestBufferTypesRoundTrip(t *testing.T) {
+	stone := identifier.Identifier{Namespace: "minecraft", Value: "stone"}
+	inline := "inline"
+
+	tests := []struct {
+		name  string
+		write func(b *Buffer) error
+		read  func(b *Buffer) (any, error)
+		want  any
+	}{
+		{
+			name:  "block position",
+			write: func(b *Buffer) error { return b.WriteBlockPosition(coordinate.NewVector(12.7, 64, 3.2)) },
+			read:  func(b *Buffer) (any, error) { return b.ReadBlockPosition() },
+			want:  coordinate.NewVector(12, 64, 3),
+		},
+		{
+			name:  "negative block position",
+			write: func(b *Buffer) error { return b.WriteBlockPosition(coordinate.NewVector(-5.5, -64.2, -1000000.1)) },
+			read:  func(b *Buffer) (any, error) { return b.ReadBlockPosition() },
+			want:  coordinate.NewVector(-6, -65, -1000001),
+		},
+		{
+			name:  "block position at the limits",
+			write: func(b *Buffer) error { return b.WriteBlockPosition(coordinate.NewVector(-33554432, 2047, 33554431)) },
+			read:  func(b *Buffer) (any, error) { return b.ReadBlockPosition() },
+			want:  coordinate.NewVector(-33554432, 2047, 33554431),
+		},
+		{
+			name:  "angle",
+			write: func(b *Buffer) error { return b.WriteAngle(45) },
+			read:  func(b *Buffer) (any, error) { return b.ReadAngle() },
+			want:  float32(45),
+		},
+		{
+			name:  "angle past 180 wraps to negative",
+			write: func(b *Buffer) error { return b.WriteAngle(270) },
+			read:  func(b *Buffer) (any, error) { return b.ReadAngle() },
+			want:  float32(-90),
+		},
+		{
+			name:  "angle of 180",
+			write: func(b *Buffer) error { return b.WriteAngle(180) },
+			read:  func(b *Buffer) (any, error) { return b.ReadAngle() },
+			want:  float32(-180),
+		},
+		{
+			name:  "angle past a full turn",
+			write: func(b *Buffer) error { return b.WriteAngle(405) },
+			read:  func(b *Buffer) (any, error) { return b.ReadAngle() },
+			want:  float32(45),
+		},
+		{
+			name:  "negative angle",
+			write: func(b *Buffer) error { return b.WriteAngle(-90) },
+			read:  func(b *Buffer) (any, error) { return b.ReadAngle() },
+			want:  float32(-90),
+		},
+		{
+			name:  "bit set",
+			write: func(b *Buffer) error { return b.WriteBitSet(bitSetOf(70, 0, 63, 64, 69)) },
+			read:  func(b *Buffer) (any, error) { return b.ReadBitSet(2) },
+			want:  bitSetOf(70, 0, 63, 64, 69),
+		},
+		{
+			name:  "empty bit set",
+			write: func(b *Buffer) error { return b.WriteBitSet(BitSet{}) },
+			read:  func(b *Buffer) (any, error) { return b.ReadBitSet(2) },
+			want:  BitSet{},
+		},
+		{
+			name:  "fixed bit set",
+			write: func(b *Buffer) error { return b.WriteFixedBitSet(bitSetOf(13, 0, 7, 8, 12), 13) },
+			read:  func(b *Buffer) (any, error) { return b.ReadFixedBitSet(13) },
+			want:  bitSetOf(13, 0, 7, 8, 12),
+		},
+		{
+			name:  "fixed bit set across words",
+			write: func(b *Buffer) error { return b.WriteFixedBitSet(bitSetOf(100, 1, 63, 64, 99), 100) },
+			read:  func(b *Buffer) (any, error) { return b.ReadFixedBitSet(100) },
+			want:  bitSetOf(100, 1, 63, 64, 99),
+		},
+		{
+			name:  "present optional",
+			write: func(b *Buffer) error { return WriteOptional(b, *optional.Of("value"), writeString) },
+			read:  func(b *Buffer) (any, error) { return ReadOptional(b, (*Buffer).ReadString) },
+			want:  *optional.Of("value"),
+		},
+		{
+			name:  "empty optional",
+			write: func(b *Buffer) error { return WriteOptional(b, *optional.Empty[string](), writeString) },
+			read:  func(b *Buffer) (any, error) { return ReadOptional(b, (*Buffer).ReadString) },
+			want:  *optional.Empty[string](),
+		},
+		{
+			name:  "prefixed array",
+			write: func(b *Buffer) error { return WritePrefixedArray(b, []int32{1, -1, 300}, (*Buffer).WriteVarInt) },
+			read:  func(b *Buffer) (any, error) { return ReadPrefixedArray(b, 3, (*Buffer).ReadVarInt) },
+			want:  []int32{1, -1, 300},
+		},
+		{
+			name:  "ID or value as ID",
+			write: func(b *Buffer) error { return WriteIDOr(b, IDOr[string]{ID: 5}, writeString) },
+			read:  func(b *Buffer) (any, error) { return ReadIDOr(b, (*Buffer).ReadString) },
+			want:  IDOr[string]{ID: 5},
+		},
+		{
+			name:  "ID or value as ID zero",
+			write: func(b *Buffer) error { return WriteIDOr(b, IDOr[string]{ID: 0}, writeString) },
+			read:  func(b *Buffer) (any, error) { return ReadIDOr(b, (*Buffer).ReadString) },
+			want:  IDOr[string]{ID: 0},
+		},
+		{
+			name:  "ID or value as inline value",
+			write: func(b *Buffer) error { return WriteIDOr(b, IDOr[string]{Value: &inline}, writeString) },
+			read:  func(b *Buffer) (any, error) { return ReadIDOr(b, (*Buffer).ReadString) },
+			want:  IDOr[string]{Value: &inline},
+		},
+		{
+			name:  "ID set as tag",
+			write: func(b *Buffer) error { return b.WriteIDSet(IDSet{Tag: &stone}) },
+			read:  func(b *Buffer) (any, error) { return b.ReadIDSet(4) },
+			want:  IDSet{Tag: &stone},
+		},
+		{
+			name:  "ID set as IDs",
+			write: func(b *Buffer) error { return b.WriteIDSet(IDSet{IDs: []int32{3, 1, 4}}) },
+			read:  func(b *Buffer) (any, error) { return b.ReadIDSet(4) },
+			want:  IDSet{IDs: []int32{3, 1, 4}},
+		},
+		{
+			name:  "empty ID set",
+			write: func(b *Buffer) error { return b.WriteIDSet(IDSet{}) },
+			read:  func(b *Buffer) (any, error) { return b.ReadIDSet(4) },
+			want:  IDSet{IDs: []int32{}},
+		},
+		{
+			name:  "identifier",
+			write: func(b *Buffer) error { return b.WriteIdentifier(stone) },
+			read:  func(b *Buffer) (any, error) { return b.ReadIdentifier() },
+			want:  &stone,
+		},
+		{
+			name:  "identifier without namespace",
+			write: func(b *Buffer) error { return b.WriteString("stone") },
+			read:  func(b *Buffer) (any, error) { return b.ReadIdentifier() },
+			want:  &stone,
+		},
+		{
+			name: "teleport flags",
+			write: func(b *Buffer) error {
+				return b.WriteTeleportFlags(TeleportRelativeX | TeleportRelativeYaw | TeleportRotateVelocity)
+			},
+			read: func(b *Buffer) (any, error) { return b.ReadTeleportFlags() },
+			want: TeleportRelativeX | TeleportRelativeYaw | TeleportRotateVelocity,
+		},
+		{
+			name:  "no teleport flags",
+			write: func(b *Buffer) error { return b.WriteTeleportFlags(0) },
+			read:  func(b *Buffer) (any, error) { return b.ReadTeleportFlags() },
+			want:  TeleportFlags(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBuffer(nil)
+			if err := tt.write(buf); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			got, err := tt.read(buf)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("%d bytes left after reading", buf.Len())
+			}
+		})
+	}
+}
+
+func TestBufferTypesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(b *Buffer) error
+		read    func(b *Buffer) error
+		wantErr error // Checked with errors.Is when set
+	}{
+		{
+			name:  "ID set over the limit",
+			write: func(b *Buffer) error { return b.WriteIDSet(IDSet{IDs: []int32{1, 2, 3, 4, 5}}) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadIDSet(4)
+				return err
+			},
+			wantErr: ErrValueTooLarge,
+		},
+		{
+			name:  "ID set claiming more IDs than sent",
+			write: func(b *Buffer) error { return b.WriteVarInt(1000) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadIDSet(math.MaxInt32)
+				return err
+			},
+			wantErr: ErrValueTooLarge,
+		},
+		{
+			name:  "ID set with negative count",
+			write: func(b *Buffer) error { return b.WriteVarInt(-1) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadIDSet(4)
+				return err
+			},
+			wantErr: ErrNegativeLength,
+		},
+		{
+			name:  "bit set over the limit",
+			write: func(b *Buffer) error { return b.WriteBitSet(NewBitSet(192)) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadBitSet(2)
+				return err
+			},
+			wantErr: ErrValueTooLarge,
+		},
+		{
+			name:  "invalid identifier",
+			write: func(b *Buffer) error { return b.WriteString("Minecraft:Stone") },
+			read: func(b *Buffer) error {
+				_, err := b.ReadIdentifier()
+				return err
+			},
+			wantErr: identifier.ErrInvalidNamespace,
+		},
+		{
+			name:  "identifier with invalid value",
+			write: func(b *Buffer) error { return b.WriteString("minecraft:stone block") },
+			read: func(b *Buffer) error {
+				_, err := b.ReadIdentifier()
+				return err
+			},
+			wantErr: identifier.ErrInvalidValue,
+		},
+		{
+			name:  "truncated LpVec3",
+			write: func(b *Buffer) error { return b.WriteByte(0x05) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadLpVec3()
+				return err
+			},
+		},
+		{
+			name:  "truncated fixed bit set",
+			write: func(b *Buffer) error { return b.WriteByte(0xFF) },
+			read: func(b *Buffer) error {
+				_, err := b.ReadFixedBitSet(9)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBuffer(nil)
+			if err := tt.write(buf); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			err := tt.read(buf)
+			if err == nil {
+				t.Fatal("read succeeded")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLpVec3RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *coordinate.Vector
+		want *coordinate.Vector
+		size int // Encoded length in bytes
+	}{
+		{name: "zero", in: coordinate.NewVector(0, 0, 0), want: coordinate.NewVector(0, 0, 0), size: 1},
+		{name: "below epsilon", in: coordinate.NewVector(1e-5, -1e-5, 0), want: coordinate.NewVector(0, 0, 0), size: 1},
+		{name: "small", in: coordinate.NewVector(0.1, -0.2, 0.05), want: coordinate.NewVector(0.1, -0.2, 0.05), size: 6},
+		{name: "scale of three", in: coordinate.NewVector(2.5, -3, 0.75), want: coordinate.NewVector(2.5, -3, 0.75), size: 6},
+		{name: "scale above three", in: coordinate.NewVector(10, -7.5, 3), want: coordinate.NewVector(10, -7.5, 3), size: 7},
+		{name: "large scale", in: coordinate.NewVector(-1000, 250, 999.5), want: coordinate.NewVector(-1000, 250, 999.5), size: 8},
+		{name: "NaN is zeroed", in: coordinate.NewVector(math.NaN(), 1, 0), want: coordinate.NewVector(0, 1, 0), size: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBuffer(nil)
+			if err := buf.WriteLpVec3(tt.in); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if buf.Len() != tt.size {
+				t.Errorf("encoded in %d bytes, want %d", buf.Len(), tt.size)
+			}
+
+			got, err := buf.ReadLpVec3()
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("%d bytes left after reading", buf.Len())
+			}
+
+			// Each component is quantised to 15 bits of the shared scale
+			scale := math.Ceil(math.Max(math.Abs(tt.want.X()), math.Max(math.Abs(tt.want.Y()), math.Abs(tt.want.Z()))))
+			tolerance := scale / lpVec3Steps
+			if math.Abs(got.X()-tt.want.X()) > tolerance ||
+				math.Abs(got.Y()-tt.want.Y()) > tolerance ||
+				math.Abs(got.Z()-tt.want.Z()) > tolerance {
+				t.Errorf("got (%g, %g, %g), want (%g, %g, %g) within %g",
+					got.X(), got.Y(), got.Z(), tt.want.X(), tt.want.Y(), tt.want.Z(), tolerance)
+			}
+		})
+	}
+}
+
+func TestBitSet(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		bits []int
+	}{
+		{name: "within a word", size: 13, bits: []int{0, 5, 12}},
+		{name: "word boundary", size: 65, bits: []int{63, 64}},
+		{name: "several words", size: 130, bits: []int{1, 64, 127, 129}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bitSetOf(tt.size, tt.bits...)
+			if len(s) != (tt.size+63)/64 {
+				t.Fatalf("%d words for %d bits", len(s), tt.size)
+			}
+			for i := 0; i < tt.size+64; i++ {
+				want := false
+				for _, bit := range tt.bits {
+					want = want || bit == i
+				}
+				if s.Get(i) != want {
+					t.Errorf("bit %d is %v, want %v", i, s.Get(i), want)
+				}
+			}
+
+			s.Clear(tt.bits[0])
+			s.Clear(tt.size + 64) // Out of range, must not panic
+			if s.Get(tt.bits[0]) {
+				t.Errorf("bit %d still set after Clear", tt.bits[0])
+			}
+		})
+	}
+}
+
+func TestTeleportFlagsHas(t *testing.T) {
+	flags := TeleportRelativeX | TeleportRelativeY | TeleportRotateVelocity
+
+	tests := []struct {
+		flag TeleportFlags
+		want bool
+	}{
+		{TeleportRelativeX, true},
+		{TeleportRelativeX | TeleportRelativeY, true},
+		{TeleportRotateVelocity, true},
+		{TeleportRelativeZ, false},
+		{TeleportRelativeX | TeleportRelativeZ, false},
+		{0, true},
+	}
+
+	for _, tt := range tests {
+		if got := flags.Has(tt.flag); got != tt.want {
+			t.Errorf("Has(%#x) = %v, want %v", tt.flag, got, tt.want)
+		}
+	}
+}
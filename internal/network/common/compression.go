@@ -0,0 +1,101 @@
+package common
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// DefaultCompressionThreshold is the vanilla network-compression-threshold.
+	DefaultCompressionThreshold = 256
+	// CompressionDisabled turns off protocol compression when used as a threshold.
+	CompressionDisabled = -1
+	// MaxUncompressedLength is the largest decompressed packet vanilla accepts (2^23).
+	MaxUncompressedLength = 8388608
+)
+
+var (
+	ErrBadlyCompressed = errors.New("badly compressed packet")
+)
+
+var (
+	zlibWriters = sync.Pool{
+		New: func() any { return zlib.NewWriter(nil) },
+	}
+	zlibReaders sync.Pool
+)
+
+// compressFrame writes an uncompressed packet (ID and payload) to out in the compressed framing.
+// Packets below threshold are sent with a zero data length and no compression.
+func compressFrame(out *Buffer, packet []byte, threshold int) error {
+	if len(packet) < threshold {
+		if err := out.WriteVarInt(0); err != nil {
+			return err
+		}
+		_, err := out.Write(packet)
+		return err
+	}
+
+	if err := out.WriteVarInt(int32(len(packet))); err != nil {
+		return err
+	}
+
+	zw := zlibWriters.Get().(*zlib.Writer)
+	defer zlibWriters.Put(zw)
+	zw.Reset(out)
+
+	if _, err := zw.Write(packet); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// decompressFrame unwraps a compressed-framing packet into its ID and payload.
+func decompressFrame(frame []byte, threshold int) ([]byte, error) {
+	buf := NewBuffer(frame)
+	dataLength, err := buf.ReadVarInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading data length: %w", err)
+	}
+
+	if dataLength == 0 {
+		return buf.Bytes(), nil
+	}
+	if int(dataLength) < threshold {
+		return nil, fmt.Errorf("%w: size %d is below server threshold %d", ErrBadlyCompressed, dataLength, threshold)
+	}
+	if dataLength > MaxUncompressedLength {
+		return nil, fmt.Errorf("%w: size %d is larger than protocol maximum %d", ErrBadlyCompressed, dataLength, MaxUncompressedLength)
+	}
+
+	zr, err := getZlibReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadlyCompressed, err)
+	}
+	defer zlibReaders.Put(zr)
+
+	packet := make([]byte, dataLength)
+	if _, err := io.ReadFull(zr, packet); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadlyCompressed, err)
+	}
+	// The stream must end exactly at the declared length
+	var extra [1]byte
+	if n, _ := zr.Read(extra[:]); n != 0 {
+		return nil, fmt.Errorf("%w: data exceeds declared size %d", ErrBadlyCompressed, dataLength)
+	}
+	return packet, nil
+}
+
+func getZlibReader(r io.Reader) (io.ReadCloser, error) {
+	if zr, ok := zlibReaders.Get().(io.ReadCloser); ok {
+		if err := zr.(zlib.Resetter).Reset(r, nil); err != nil {
+			return nil, err
+		}
+		return zr, nil
+	}
+	return zlib.NewReader(r)
+}
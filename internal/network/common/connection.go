@@ -1,75 +1,338 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net"
 	"sync"
-	"time"
 )
 
 // PlayerConnection represents a client connection
 type PlayerConnection struct {
-	conn  net.Conn
-	state ConnectionState
-	mu    sync.RWMutex
+	conn       net.Conn
+	remoteAddr net.Addr
+	state      ConnectionState
+	mu         sync.RWMutex
+	writer     *writer
+	closed     bool
+
+	compressionThreshold int
+	compressionEnabled   bool
+
+	protocolVersion int32
+	serverAddress   string
+	serverPort      uint16
+	transferred     bool
+
+	loginQueries     map[int32]LoginQueryHandler
+	nextLoginQueryID int32
+
+	gameProfile GameProfile
+	verifyToken []byte
+	logger      *log.Logger
+	keepAlive   keepAlive
+
+	sendInterceptor SendInterceptor
+	validator       PacketValidator
+}
+
+// SendInterceptor sees every packet before it is sent. It returns the packet to send in
+// its place, or false to drop it.
+type SendInterceptor func(pc *PlayerConnection, packet ClientboundPacket) (ClientboundPacket, bool)
+
+// PacketValidator checks that a clientbound packet may be sent in a connection state.
+type PacketValidator interface {
+	ValidateClientBound(state ConnectionState, packet ClientboundPacket) error
 }
 
 // NewPlayerConnection creates a new player connection
 func NewPlayerConnection(conn net.Conn) *PlayerConnection {
-	return &PlayerConnection{
-		conn:  conn,
-		state: Handshake,
+	pc := &PlayerConnection{
+		conn:                 conn,
+		remoteAddr:           conn.RemoteAddr(),
+		state:                Handshake,
+		compressionThreshold: CompressionDisabled,
+		logger:               log.New(log.Writer(), fmt.Sprintf("[%s] ", conn.RemoteAddr()), log.Flags()),
+		writer:               newWriter(),
 	}
+	go func() {
+		pc.writer.run(conn, pc.logger)
+		pc.markClosed()
+	}()
+	return pc
 }
 
-// SendRaw to send raw bytes
+// Logger returns a logger whose lines are prefixed with the client address.
+func (pc *PlayerConnection) Logger() *log.Logger {
+	return pc.logger
+}
+
+// SendRaw queues raw bytes, bypassing packet framing, and flushes them.
 func (pc *PlayerConnection) SendRaw(data []byte) error {
+	conn := pc.Conn()
+	if conn == nil {
+		return ErrConnectionClosed
+	}
+
+	buf := getBuffer()
+	buf.Write(data)
+	if err := pc.enqueue(conn, buf); err != nil {
+		return err
+	}
+	pc.Flush()
+	return nil
+}
+
+// SetSendInterceptor sets the function every packet passes through in SendPacket.
+func (pc *PlayerConnection) SetSendInterceptor(interceptor SendInterceptor) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.sendInterceptor = interceptor
+}
+
+// SetPacketValidator sets what SendPacket checks packets against. Packets it rejects are
+// not sent.
+func (pc *PlayerConnection) SetPacketValidator(validator PacketValidator) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.validator = validator
+}
+
+// SendPacket queues a packet for the writer goroutine. Packets sent in Play go out with the
+// next Flush, which the server calls once per tick; earlier states flush right away.
+// A packet dropped by the send interceptor is not an error.
+func (pc *PlayerConnection) SendPacket(p ClientboundPacket) error {
 	pc.mu.RLock()
 	conn := pc.conn
+	closed := pc.closed
+	state := pc.state
+	interceptor := pc.sendInterceptor
+	validator := pc.validator
 	pc.mu.RUnlock()
 
-	fmt.Println("sending raw data")
+	if closed {
+		return ErrConnectionClosed
+	}
 
-	if conn == nil {
-		return fmt.Errorf("connection is closed")
+	if interceptor != nil {
+		var ok bool
+		if p, ok = interceptor(pc, p); !ok || p == nil {
+			return nil
+		}
+	}
+	if validator != nil {
+		if err := validator.ValidateClientBound(state, p); err != nil {
+			pc.logger.Printf("Not sending packet: %v", err)
+			return err
+		}
 	}
 
-	if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+	packet := getBuffer()
+	defer putBuffer(packet)
+	packet.WriteVarInt(p.ID())
+	p.Write(packet)
+
+	if threshold, enabled := pc.compression(); enabled {
+		compressed := getBuffer()
+		defer putBuffer(compressed)
+		if err := compressFrame(compressed, packet.Bytes(), threshold); err != nil {
+			return fmt.Errorf("compressing packet 0x%02X: %w", p.ID(), err)
+		}
+		packet = compressed
+	}
+
+	// Combine: length + packet
+	frame := getBuffer()
+	frame.WriteVarInt(int32(packet.Len()))
+	frame.Write(packet.Bytes())
+
+	if err := pc.enqueue(conn, frame); err != nil {
 		return err
 	}
+	if state != Play {
+		pc.Flush()
+	}
+	return nil
+}
+
+// Flush asks the writer goroutine to send every queued packet. It does not wait for the write.
+func (pc *PlayerConnection) Flush() {
+	pc.writer.requestFlush()
+}
 
-	_, err := conn.Write(data)
+// enqueue hands a frame to the writer. A client whose queue overflows is not reading fast
+// enough and is dropped without waiting for the queue to drain.
+func (pc *PlayerConnection) enqueue(conn net.Conn, buf *Buffer) error {
+	err := pc.writer.enqueue(outboundFrame{conn: conn, buf: buf})
+	if errors.Is(err, ErrSlowConsumer) {
+		pc.logger.Printf("Outbound queue overflowed, dropping client")
+		pc.abort()
+	}
 	return err
 }
 
-// SendPacket sends a packet to the client
-func (pc *PlayerConnection) SendPacket(p ClientboundPacket) error {
+// DecodeFrame turns a length-delimited frame read from the client into a buffer positioned
+// at the packet ID, decompressing it if compression is enabled.
+func (pc *PlayerConnection) DecodeFrame(frame []byte) (*Buffer, error) {
+	threshold, enabled := pc.compression()
+	if !enabled {
+		return NewBuffer(frame), nil
+	}
+
+	packet, err := decompressFrame(frame, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return NewBuffer(packet), nil
+}
+
+// SetCompressionThreshold sets the threshold used once compression is enabled.
+// CompressionDisabled keeps this connection uncompressed.
+func (pc *PlayerConnection) SetCompressionThreshold(threshold int) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.compressionThreshold = threshold
+}
+
+// GetCompressionThreshold returns the configured compression threshold.
+func (pc *PlayerConnection) GetCompressionThreshold() int {
 	pc.mu.RLock()
-	conn := pc.conn
-	pc.mu.RUnlock()
+	defer pc.mu.RUnlock()
+	return pc.compressionThreshold
+}
 
-	if conn == nil {
-		return fmt.Errorf("connection is closed")
+// EnableCompression switches both directions to compressed framing. It must be called
+// right after the Set Compression packet has been sent.
+func (pc *PlayerConnection) EnableCompression() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.compressionEnabled = pc.compressionThreshold >= 0
+}
+
+func (pc *PlayerConnection) compression() (int, bool) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.compressionThreshold, pc.compressionEnabled
+}
+
+// Conn returns the underlying connection, which is encrypted once EnableEncryption was called.
+// It returns nil once the connection is closing.
+func (pc *PlayerConnection) Conn() net.Conn {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	if pc.closed {
+		return nil
 	}
+	return pc.conn
+}
 
-	if err := conn.SetWriteDeadline(time.Now().Add(30 * time.Second)); err != nil {
+// EnableEncryption wraps the connection in AES/CFB8 keyed by the shared secret.
+// Every byte read or written afterwards is encrypted.
+func (pc *PlayerConnection) EnableEncryption(sharedSecret []byte) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.closed {
+		return ErrConnectionClosed
+	}
+	if _, ok := pc.conn.(*encryptedConn); ok {
+		return fmt.Errorf("encryption already enabled")
+	}
+
+	conn, err := newEncryptedConn(pc.conn, sharedSecret)
+	if err != nil {
 		return err
 	}
+	pc.conn = conn
+	return nil
+}
 
-	buf := NewBuffer(nil)
-	p.Write(buf)
+// IsEncrypted reports whether the connection has been switched to encryption.
+func (pc *PlayerConnection) IsEncrypted() bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	_, ok := pc.conn.(*encryptedConn)
+	return ok
+}
 
-	header := NewBuffer(nil)
-	header.WriteVarInt(int32(buf.Len() + 1))
-	header.WriteVarInt(p.ID())
+// RemoteAddr returns the client's address, which a proxy may have forwarded.
+func (pc *PlayerConnection) RemoteAddr() net.Addr {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.remoteAddr
+}
 
-	// Combine: header + payload
-	final := NewBuffer(nil)
-	final.Write(header.Bytes())
-	final.Write(buf.Bytes())
+// SetRemoteAddr replaces the client's address with one forwarded by a trusted proxy.
+func (pc *PlayerConnection) SetRemoteAddr(addr net.Addr) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.remoteAddr = addr
+}
 
-	_, err := conn.Write(final.Bytes())
-	return err
+// SetHandshake records what the client sent in its handshake.
+func (pc *PlayerConnection) SetHandshake(protocolVersion int32, serverAddress string, serverPort uint16) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.protocolVersion = protocolVersion
+	pc.serverAddress = serverAddress
+	pc.serverPort = serverPort
+}
+
+// GetProtocolVersion returns the protocol version the client announced in its handshake.
+func (pc *PlayerConnection) GetProtocolVersion() int32 {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.protocolVersion
+}
+
+// GetServerAddress returns the host name and port the client used to connect.
+func (pc *PlayerConnection) GetServerAddress() (string, uint16) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.serverAddress, pc.serverPort
+}
+
+// SetTransferred marks a connection whose client was sent here by another server.
+func (pc *PlayerConnection) SetTransferred(transferred bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.transferred = transferred
+}
+
+// IsTransferred reports whether the client connected with the transfer intent.
+func (pc *PlayerConnection) IsTransferred() bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.transferred
+}
+
+// SetGameProfile attaches the profile of the account logging in on this connection.
+func (pc *PlayerConnection) SetGameProfile(profile GameProfile) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.gameProfile = profile
+}
+
+// GetGameProfile returns the profile attached during login.
+func (pc *PlayerConnection) GetGameProfile() GameProfile {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.gameProfile
+}
+
+// SetVerifyToken stores the token sent in the Encryption Request.
+func (pc *PlayerConnection) SetVerifyToken(token []byte) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.verifyToken = token
+}
+
+// GetVerifyToken returns the token sent in the Encryption Request.
+func (pc *PlayerConnection) GetVerifyToken() []byte {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.verifyToken
 }
 
 // SetState updates the connection state
@@ -86,15 +349,33 @@ func (pc *PlayerConnection) GetState() ConnectionState {
 	return pc.state
 }
 
-// Close closes the player connection
+// Close stops accepting packets and lets the writer goroutine send everything still queued
+// before it closes the connection. It does not wait for that; the writer gives up after
+// WriteTimeout.
 func (pc *PlayerConnection) Close() error {
+	pc.markClosed()
+	pc.writer.stop()
+	return nil
+}
+
+// Done returns a channel that is closed once the writer goroutine has closed the connection.
+func (pc *PlayerConnection) Done() <-chan struct{} {
+	return pc.writer.done
+}
+
+// abort closes the connection at once, discarding anything still queued.
+func (pc *PlayerConnection) abort() {
+	pc.markClosed()
+	pc.writer.stop()
+
+	pc.mu.RLock()
+	conn := pc.conn
+	pc.mu.RUnlock()
+	_ = conn.Close()
+}
+
+func (pc *PlayerConnection) markClosed() {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
-
-	if pc.conn != nil {
-		err := pc.conn.Close()
-		pc.conn = nil
-		return err
-	}
-	return nil
+	pc.closed = true
 }
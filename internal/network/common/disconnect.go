@@ -0,0 +1,55 @@
+package common
+
+import "fmt"
+
+// Text is a formatted message in both wire formats, such as a component.Component.
+type Text interface {
+	JSON() string
+	NBT() Tag
+}
+
+// DisconnectPacket tells the client why it is being disconnected. It is defined here rather
+// than with the other clientbound packets so PlayerConnection can send it; its ID and
+// encoding depend on State, which must be Login, Configuration or Play.
+type DisconnectPacket struct {
+	State  ConnectionState
+	Reason Text
+}
+
+func (p *DisconnectPacket) ID() int32 {
+	switch p.State {
+	case Login:
+		return 0x00
+	case Configuration:
+		return 0x02
+	default:
+		return 0x1C
+	}
+}
+
+func (p *DisconnectPacket) Write(buf *Buffer) {
+	if p.State == Login {
+		buf.WriteString(p.Reason.JSON())
+		return
+	}
+	buf.WriteNBT(p.Reason.NBT())
+}
+
+// Disconnect sends the Disconnect packet of the current state with reason and closes the
+// connection. Before Login the client cannot show a reason, so the connection is just closed.
+func (pc *PlayerConnection) Disconnect(reason Text) error {
+	state := pc.GetState()
+
+	var sendErr error
+	if state == Login || state == Configuration || state == Play {
+		sendErr = pc.SendPacket(&DisconnectPacket{State: state, Reason: reason})
+	}
+
+	if err := pc.Close(); err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return fmt.Errorf("sending disconnect: %w", sendErr)
+	}
+	return nil
+}
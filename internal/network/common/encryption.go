@@ -0,0 +1,86 @@
+package common
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"net"
+	"sync"
+)
+
+// cfb8 implements AES/CFB8, the stream cipher used by the Minecraft protocol.
+// The standard library only ships full-block CFB.
+type cfb8 struct {
+	block   cipher.Block
+	iv      []byte
+	tmp     []byte
+	decrypt bool
+}
+
+func newCFB8(block cipher.Block, iv []byte, decrypt bool) cipher.Stream {
+	size := block.BlockSize()
+	x := &cfb8{
+		block:   block,
+		iv:      make([]byte, size),
+		tmp:     make([]byte, size),
+		decrypt: decrypt,
+	}
+	copy(x.iv, iv)
+	return x
+}
+
+func (x *cfb8) XORKeyStream(dst, src []byte) {
+	last := len(x.iv) - 1
+	for i, in := range src {
+		x.block.Encrypt(x.tmp, x.iv)
+		out := in ^ x.tmp[0]
+
+		// Shift the register left by one byte and feed back the ciphertext byte
+		copy(x.iv, x.iv[1:])
+		if x.decrypt {
+			x.iv[last] = in
+		} else {
+			x.iv[last] = out
+		}
+		dst[i] = out
+	}
+}
+
+// encryptedConn encrypts everything written to and decrypts everything read from a connection.
+type encryptedConn struct {
+	net.Conn
+	decrypt cipher.Stream
+	encrypt cipher.Stream
+	readMu  sync.Mutex
+	writeMu sync.Mutex
+}
+
+// newEncryptedConn wraps conn with AES/CFB8 using the shared secret as both key and IV.
+func newEncryptedConn(conn net.Conn, sharedSecret []byte) (net.Conn, error) {
+	block, err := aes.NewCipher(sharedSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedConn{
+		Conn:    conn,
+		decrypt: newCFB8(block, sharedSecret, true),
+		encrypt: newCFB8(block, sharedSecret, false),
+	}, nil
+}
+
+func (c *encryptedConn) Read(p []byte) (int, error) {
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+
+	n, err := c.Conn.Read(p)
+	c.decrypt.XORKeyStream(p[:n], p[:n])
+	return n, err
+}
+
+func (c *encryptedConn) Write(p []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	out := make([]byte, len(p))
+	c.encrypt.XORKeyStream(out, p)
+	return c.Conn.Write(out)
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"Veloce/internal/objects/optional"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// Property is a signed profile property, such as the player's skin textures.
+type Property struct {
+	Name      string
+	Value     string
+	Signature optional.Optional[string]
+}
+
+// GameProfile identifies the account behind a connection.
+type GameProfile struct {
+	UUID       uuid.UUID
+	Name       string
+	Properties []Property
+}
+
+// WriteGameProfile writes the profile as UUID, name and properties.
+func (b *Buffer) WriteGameProfile(profile GameProfile) error {
+	if err := b.WriteUUID(profile.UUID); err != nil {
+		return err
+	}
+	if err := b.WriteString(profile.Name); err != nil {
+		return err
+	}
+
+	if err := b.WriteVarInt(int32(len(profile.Properties))); err != nil {
+		return err
+	}
+	for _, property := range profile.Properties {
+		if err := b.WriteString(property.Name); err != nil {
+			return err
+		}
+		if err := b.WriteString(property.Value); err != nil {
+			return err
+		}
+
+		signature := property.Signature
+		if err := b.WriteBool(signature.IsPresent()); err != nil {
+			return err
+		}
+		if signature.IsPresent() {
+			if err := b.WriteString(signature.Get()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Limits vanilla puts on the fields of a game profile.
+const (
+	MaxProfileNameLength     = 16
+	MaxProfileProperties     = 16
+	maxPropertyNameLength    = 64
+	maxPropertyValueLength   = MaxStringLength
+	maxPropertySignatureSize = 1024
+)
+
+// ReadGameProfile reads a profile written by WriteGameProfile.
+func (b *Buffer) ReadGameProfile() (GameProfile, error) {
+	var profile GameProfile
+	var err error
+	if profile.UUID, err = b.ReadUUID(); err != nil {
+		return GameProfile{}, err
+	}
+	if profile.Name, err = b.ReadStringMax(MaxProfileNameLength); err != nil {
+		return GameProfile{}, err
+	}
+	if profile.Properties, err = ReadPrefixedArray(b, MaxProfileProperties, readProperty); err != nil {
+		return GameProfile{}, fmt.Errorf("reading profile properties: %w", err)
+	}
+	return profile, nil
+}
+
+func readProperty(b *Buffer) (Property, error) {
+	var property Property
+	var err error
+	if property.Name, err = b.ReadStringMax(maxPropertyNameLength); err != nil {
+		return Property{}, err
+	}
+	if property.Value, err = b.ReadStringMax(maxPropertyValueLength); err != nil {
+		return Property{}, err
+	}
+
+	signed, err := b.ReadBool()
+	if err != nil {
+		return Property{}, err
+	}
+	if signed {
+		signature, err := b.ReadStringMax(maxPropertySignatureSize)
+		if err != nil {
+			return Property{}, err
+		}
+		property.Signature = *optional.Of(signature)
+	}
+	return property, nil
+}
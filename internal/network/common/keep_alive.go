@@ -0,0 +1,72 @@
+package common
+
+import "time"
+
+// keepAlive tracks the Keep Alive exchange of a connection in Configuration or Play.
+type keepAlive struct {
+	id      int64
+	sentAt  time.Time
+	pending bool
+	latency time.Duration
+}
+
+// StartKeepAlive records that a Keep Alive with the given ID was sent now.
+func (pc *PlayerConnection) StartKeepAlive(id int64) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.keepAlive.id = id
+	pc.keepAlive.sentAt = time.Now()
+	pc.keepAlive.pending = true
+}
+
+// CancelKeepAlive forgets the pending Keep Alive with the given ID, for one that could not be
+// sent. The next one is due an interval after it.
+func (pc *PlayerConnection) CancelKeepAlive(id int64) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.keepAlive.pending && pc.keepAlive.id == id {
+		pc.keepAlive.pending = false
+	}
+}
+
+// AcknowledgeKeepAlive checks the ID echoed by the client and updates the latency.
+// It reports false if the ID does not answer the pending Keep Alive.
+func (pc *PlayerConnection) AcknowledgeKeepAlive(id int64) bool {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if !pc.keepAlive.pending || pc.keepAlive.id != id {
+		return false
+	}
+	pc.keepAlive.pending = false
+
+	// Smoothed like the vanilla server, so a single slow answer doesn't dominate
+	sample := time.Since(pc.keepAlive.sentAt)
+	if pc.keepAlive.latency == 0 {
+		pc.keepAlive.latency = sample
+	} else {
+		pc.keepAlive.latency = (pc.keepAlive.latency*3 + sample) / 4
+	}
+	return true
+}
+
+// KeepAliveDue reports whether the last Keep Alive was answered and sent at least interval ago.
+func (pc *PlayerConnection) KeepAliveDue(interval time.Duration) bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return !pc.keepAlive.pending && time.Since(pc.keepAlive.sentAt) >= interval
+}
+
+// KeepAliveExpired reports whether the pending Keep Alive went unanswered for longer than timeout.
+func (pc *PlayerConnection) KeepAliveExpired(timeout time.Duration) bool {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.keepAlive.pending && time.Since(pc.keepAlive.sentAt) > timeout
+}
+
+// GetLatency returns the smoothed round trip time measured by Keep Alives.
+func (pc *PlayerConnection) GetLatency() time.Duration {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+	return pc.keepAlive.latency
+}
@@ -0,0 +1,30 @@
+package common
+
+// LoginQueryHandler receives the answer to a Login Plugin Request. understood is false if the
+// client did not recognise the channel, in which case data is nil.
+type LoginQueryHandler func(data []byte, understood bool)
+
+// AddLoginQuery registers the handler for a Login Plugin Request about to be sent and
+// returns the message ID to send it with.
+func (pc *PlayerConnection) AddLoginQuery(handler LoginQueryHandler) int32 {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.loginQueries == nil {
+		pc.loginQueries = make(map[int32]LoginQueryHandler)
+	}
+	id := pc.nextLoginQueryID
+	pc.nextLoginQueryID++
+	pc.loginQueries[id] = handler
+	return id
+}
+
+// TakeLoginQuery removes and returns the handler waiting for the given message ID.
+func (pc *PlayerConnection) TakeLoginQuery(id int32) (LoginQueryHandler, bool) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	handler, ok := pc.loginQueries[id]
+	delete(pc.loginQueries, id)
+	return handler, ok
+}
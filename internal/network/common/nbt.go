@@ -0,0 +1,162 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TagType identifies the kind of payload carried by an NBT tag.
+type TagType byte
+
+const (
+	TagEnd TagType = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+var tagTypeNames = [...]string{
+	TagEnd:       "TAG_End",
+	TagByte:      "TAG_Byte",
+	TagShort:     "TAG_Short",
+	TagInt:       "TAG_Int",
+	TagLong:      "TAG_Long",
+	TagFloat:     "TAG_Float",
+	TagDouble:    "TAG_Double",
+	TagByteArray: "TAG_Byte_Array",
+	TagString:    "TAG_String",
+	TagList:      "TAG_List",
+	TagCompound:  "TAG_Compound",
+	TagIntArray:  "TAG_Int_Array",
+	TagLongArray: "TAG_Long_Array",
+}
+
+// String returns the canonical name of the tag type.
+func (t TagType) String() string {
+	if int(t) < len(tagTypeNames) {
+		return tagTypeNames[t]
+	}
+	return fmt.Sprintf("TAG_Unknown(%d)", byte(t))
+}
+
+// Tag is a single NBT value.
+type Tag interface {
+	Type() TagType
+}
+
+type (
+	ByteTag      int8
+	ShortTag     int16
+	IntTag       int32
+	LongTag      int64
+	FloatTag     float32
+	DoubleTag    float64
+	ByteArrayTag []byte
+	StringTag    string
+	IntArrayTag  []int32
+	LongArrayTag []int64
+)
+
+// ListTag is a homogeneous list of tags. ElemType is only consulted when the list is empty.
+type ListTag struct {
+	ElemType TagType
+	Elems    []Tag
+}
+
+// CompoundTag is a set of named tags.
+type CompoundTag map[string]Tag
+
+func (ByteTag) Type() TagType      { return TagByte }
+func (ShortTag) Type() TagType     { return TagShort }
+func (IntTag) Type() TagType       { return TagInt }
+func (LongTag) Type() TagType      { return TagLong }
+func (FloatTag) Type() TagType     { return TagFloat }
+func (DoubleTag) Type() TagType    { return TagDouble }
+func (ByteArrayTag) Type() TagType { return TagByteArray }
+func (StringTag) Type() TagType    { return TagString }
+func (*ListTag) Type() TagType     { return TagList }
+func (CompoundTag) Type() TagType  { return TagCompound }
+func (IntArrayTag) Type() TagType  { return TagIntArray }
+func (LongArrayTag) Type() TagType { return TagLongArray }
+
+// BoolTag returns the byte tag vanilla uses to store booleans.
+func BoolTag(v bool) ByteTag {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// NewListTag creates a list, deriving the element type from the first element.
+func NewListTag(elems ...Tag) *ListTag {
+	l := &ListTag{Elems: elems}
+	if len(elems) > 0 {
+		l.ElemType = elems[0].Type()
+	}
+	return l
+}
+
+// Add appends a tag to the list, failing if its type does not match the list.
+func (l *ListTag) Add(tag Tag) error {
+	if len(l.Elems) == 0 {
+		l.ElemType = tag.Type()
+	} else if tag.Type() != l.ElemType {
+		return fmt.Errorf("%w: cannot add %s to list of %s", ErrNBTListType, tag.Type(), l.ElemType)
+	}
+	l.Elems = append(l.Elems, tag)
+	return nil
+}
+
+// Len returns the number of elements in the list.
+func (l *ListTag) Len() int {
+	return len(l.Elems)
+}
+
+// Keys returns the compound's keys in sorted order.
+func (c CompoundTag) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetString returns the string stored under key, if there is one.
+func (c CompoundTag) GetString(key string) (string, bool) {
+	v, ok := c[key].(StringTag)
+	return string(v), ok
+}
+
+// GetInt returns the int stored under key, if there is one.
+func (c CompoundTag) GetInt(key string) (int32, bool) {
+	v, ok := c[key].(IntTag)
+	return int32(v), ok
+}
+
+// GetBool returns the byte stored under key interpreted as a boolean.
+func (c CompoundTag) GetBool(key string) (bool, bool) {
+	v, ok := c[key].(ByteTag)
+	return v != 0, ok
+}
+
+// GetCompound returns the compound stored under key, if there is one.
+func (c CompoundTag) GetCompound(key string) (CompoundTag, bool) {
+	v, ok := c[key].(CompoundTag)
+	return v, ok
+}
+
+// GetList returns the list stored under key, if there is one.
+func (c CompoundTag) GetList(key string) (*ListTag, bool) {
+	v, ok := c[key].(*ListTag)
+	return v, ok
+}
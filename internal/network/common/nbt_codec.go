@@ -0,0 +1,614 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Errors returned while encoding or decoding NBT
+var (
+	ErrNBTUnknownTag   = errors.New("nbt: unknown tag type")
+	ErrNBTListType     = errors.New("nbt: list element type mismatch")
+	ErrNBTTooDeep      = errors.New("nbt: tag nesting too deep")
+	ErrNBTTooLarge     = errors.New("nbt: data exceeds size quota")
+	ErrNBTStringLength = errors.New("nbt: string too long")
+	ErrNBTInvalidUTF   = errors.New("nbt: malformed modified UTF-8")
+)
+
+const (
+	// MaxNBTDepth is the deepest nesting of lists and compounds accepted, matching vanilla.
+	MaxNBTDepth = 512
+	// MaxNetworkNBTBytes is the quota vanilla applies to NBT read from packets.
+	MaxNetworkNBTBytes = 2097152
+)
+
+// NBTCompression selects the outer compression applied to NBT files.
+type NBTCompression int
+
+const (
+	NBTUncompressed NBTCompression = iota
+	NBTGzip
+	NBTZlib
+)
+
+// NBTWriter encodes tags to an underlying writer.
+type NBTWriter struct {
+	w       io.Writer
+	scratch [8]byte
+}
+
+// NewNBTWriter creates a writer that encodes tags to w.
+func NewNBTWriter(w io.Writer) *NBTWriter {
+	return &NBTWriter{w: w}
+}
+
+// WriteNetwork writes tag in the nameless-root network format used since 1.20.2.
+// A nil tag is written as TAG_End.
+func (nw *NBTWriter) WriteNetwork(tag Tag) error {
+	if tag == nil {
+		return nw.writeByte(byte(TagEnd))
+	}
+	if err := nw.writeByte(byte(tag.Type())); err != nil {
+		return err
+	}
+	return nw.writePayload(tag, 0)
+}
+
+// WriteNamed writes tag with a root name, as stored in files.
+func (nw *NBTWriter) WriteNamed(name string, tag Tag) error {
+	if tag == nil {
+		return nw.writeByte(byte(TagEnd))
+	}
+	if err := nw.writeByte(byte(tag.Type())); err != nil {
+		return err
+	}
+	if err := nw.writeString(name); err != nil {
+		return err
+	}
+	return nw.writePayload(tag, 0)
+}
+
+func (nw *NBTWriter) writeByte(v byte) error {
+	nw.scratch[0] = v
+	_, err := nw.w.Write(nw.scratch[:1])
+	return err
+}
+
+func (nw *NBTWriter) writeUint16(v uint16) error {
+	binary.BigEndian.PutUint16(nw.scratch[:2], v)
+	_, err := nw.w.Write(nw.scratch[:2])
+	return err
+}
+
+func (nw *NBTWriter) writeUint32(v uint32) error {
+	binary.BigEndian.PutUint32(nw.scratch[:4], v)
+	_, err := nw.w.Write(nw.scratch[:4])
+	return err
+}
+
+func (nw *NBTWriter) writeUint64(v uint64) error {
+	binary.BigEndian.PutUint64(nw.scratch[:8], v)
+	_, err := nw.w.Write(nw.scratch[:8])
+	return err
+}
+
+func (nw *NBTWriter) writeString(s string) error {
+	data := encodeModifiedUTF8(s)
+	if len(data) > math.MaxUint16 {
+		return ErrNBTStringLength
+	}
+	if err := nw.writeUint16(uint16(len(data))); err != nil {
+		return err
+	}
+	_, err := nw.w.Write(data)
+	return err
+}
+
+func (nw *NBTWriter) writePayload(tag Tag, depth int) error {
+	if depth > MaxNBTDepth {
+		return ErrNBTTooDeep
+	}
+
+	switch t := tag.(type) {
+	case ByteTag:
+		return nw.writeByte(byte(t))
+	case ShortTag:
+		return nw.writeUint16(uint16(t))
+	case IntTag:
+		return nw.writeUint32(uint32(t))
+	case LongTag:
+		return nw.writeUint64(uint64(t))
+	case FloatTag:
+		return nw.writeUint32(math.Float32bits(float32(t)))
+	case DoubleTag:
+		return nw.writeUint64(math.Float64bits(float64(t)))
+	case ByteArrayTag:
+		if err := nw.writeUint32(uint32(len(t))); err != nil {
+			return err
+		}
+		_, err := nw.w.Write(t)
+		return err
+	case StringTag:
+		return nw.writeString(string(t))
+	case *ListTag:
+		elemType := t.ElemType
+		if len(t.Elems) == 0 {
+			elemType = TagEnd
+		}
+		if err := nw.writeByte(byte(elemType)); err != nil {
+			return err
+		}
+		if err := nw.writeUint32(uint32(len(t.Elems))); err != nil {
+			return err
+		}
+		for _, elem := range t.Elems {
+			if elem == nil || elem.Type() != elemType {
+				return fmt.Errorf("%w: expected %s", ErrNBTListType, elemType)
+			}
+			if err := nw.writePayload(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CompoundTag:
+		for _, key := range t.Keys() {
+			child := t[key]
+			if child == nil {
+				continue
+			}
+			if err := nw.writeByte(byte(child.Type())); err != nil {
+				return err
+			}
+			if err := nw.writeString(key); err != nil {
+				return err
+			}
+			if err := nw.writePayload(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nw.writeByte(byte(TagEnd))
+	case IntArrayTag:
+		if err := nw.writeUint32(uint32(len(t))); err != nil {
+			return err
+		}
+		for _, v := range t {
+			if err := nw.writeUint32(uint32(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case LongArrayTag:
+		if err := nw.writeUint32(uint32(len(t))); err != nil {
+			return err
+		}
+		for _, v := range t {
+			if err := nw.writeUint64(uint64(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %T", ErrNBTUnknownTag, tag)
+	}
+}
+
+// NBTReader decodes tags from an underlying reader, enforcing a byte quota and depth limit.
+type NBTReader struct {
+	r       io.Reader
+	quota   int64
+	sized   interface{ Len() int } // Set when r knows how many bytes it has left
+	scratch [8]byte
+}
+
+// NewNBTReader creates a reader that accepts at most quota bytes of tag data.
+// A quota of zero or less disables the limit.
+func NewNBTReader(r io.Reader, quota int64) *NBTReader {
+	if quota <= 0 {
+		quota = math.MaxInt64
+	}
+	sized, _ := r.(interface{ Len() int })
+	return &NBTReader{r: r, quota: quota, sized: sized}
+}
+
+// ReadNetwork reads a nameless-root tag. TAG_End yields a nil tag.
+func (nr *NBTReader) ReadNetwork() (Tag, error) {
+	typ, err := nr.readByte()
+	if err != nil {
+		return nil, err
+	}
+	if TagType(typ) == TagEnd {
+		return nil, nil
+	}
+	return nr.readPayload(TagType(typ), 0)
+}
+
+// ReadNamed reads a tag with a root name, as stored in files.
+func (nr *NBTReader) ReadNamed() (string, Tag, error) {
+	typ, err := nr.readByte()
+	if err != nil {
+		return "", nil, err
+	}
+	if TagType(typ) == TagEnd {
+		return "", nil, nil
+	}
+	name, err := nr.readString()
+	if err != nil {
+		return "", nil, err
+	}
+	tag, err := nr.readPayload(TagType(typ), 0)
+	return name, tag, err
+}
+
+func (nr *NBTReader) account(n int64) error {
+	if n < 0 || n > nr.quota {
+		return ErrNBTTooLarge
+	}
+	nr.quota -= n
+	return nil
+}
+
+func (nr *NBTReader) readFull(n int) ([]byte, error) {
+	if err := nr.account(int64(n)); err != nil {
+		return nil, err
+	}
+	var buf []byte
+	if n > len(nr.scratch) {
+		buf = make([]byte, n)
+	} else {
+		buf = nr.scratch[:n]
+	}
+	if _, err := io.ReadFull(nr.r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (nr *NBTReader) readByte() (byte, error) {
+	b, err := nr.readFull(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (nr *NBTReader) readUint16() (uint16, error) {
+	b, err := nr.readFull(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+func (nr *NBTReader) readUint32() (uint32, error) {
+	b, err := nr.readFull(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+func (nr *NBTReader) readUint64() (uint64, error) {
+	b, err := nr.readFull(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (nr *NBTReader) readString() (string, error) {
+	length, err := nr.readUint16()
+	if err != nil {
+		return "", err
+	}
+	data, err := nr.readFull(int(length))
+	if err != nil {
+		return "", err
+	}
+	return decodeModifiedUTF8(data)
+}
+
+// readLength reads an array or list length and charges elemSize bytes per element against the
+// quota up front, as vanilla's accounter does. When the input knows its size, lengths that
+// would need more than minSize bytes per element beyond what is left are rejected, so a
+// hostile length cannot trigger a large allocation.
+func (nr *NBTReader) readLength(elemSize, minSize int64) (int, error) {
+	n, err := nr.readUint32()
+	if err != nil {
+		return 0, err
+	}
+	length := int64(int32(n))
+	if length < 0 {
+		return 0, ErrNegativeLength
+	}
+	if nr.sized != nil && length*minSize > int64(nr.sized.Len()) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if length > nr.quota/elemSize {
+		return 0, ErrNBTTooLarge
+	}
+	if err := nr.account(length * elemSize); err != nil {
+		return 0, err
+	}
+	return int(length), nil
+}
+
+// readArray reads n elements of size bytes each, already charged by readLength. Without a
+// known input size the array grows as data arrives instead of trusting the length.
+func (nr *NBTReader) readArray(n, size int) ([]byte, error) {
+	if nr.sized != nil {
+		data := make([]byte, n*size)
+		if _, err := io.ReadFull(nr.r, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	var data bytes.Buffer
+	if _, err := io.CopyN(&data, nr.r, int64(n)*int64(size)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+// minPayloadSize is the fewest bytes a payload of typ takes.
+func minPayloadSize(typ TagType) int64 {
+	switch typ {
+	case TagShort, TagString:
+		return 2
+	case TagInt, TagFloat, TagByteArray, TagIntArray, TagLongArray:
+		return 4
+	case TagLong, TagDouble:
+		return 8
+	case TagList:
+		return 5
+	default:
+		return 1
+	}
+}
+
+func (nr *NBTReader) readPayload(typ TagType, depth int) (Tag, error) {
+	if depth > MaxNBTDepth {
+		return nil, ErrNBTTooDeep
+	}
+
+	switch typ {
+	case TagByte:
+		v, err := nr.readByte()
+		return ByteTag(v), err
+	case TagShort:
+		v, err := nr.readUint16()
+		return ShortTag(v), err
+	case TagInt:
+		v, err := nr.readUint32()
+		return IntTag(v), err
+	case TagLong:
+		v, err := nr.readUint64()
+		return LongTag(v), err
+	case TagFloat:
+		v, err := nr.readUint32()
+		return FloatTag(math.Float32frombits(v)), err
+	case TagDouble:
+		v, err := nr.readUint64()
+		return DoubleTag(math.Float64frombits(v)), err
+	case TagByteArray:
+		n, err := nr.readLength(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		data, err := nr.readArray(n, 1)
+		return ByteArrayTag(data), err
+	case TagString:
+		s, err := nr.readString()
+		return StringTag(s), err
+	case TagList:
+		elemType, err := nr.readByte()
+		if err != nil {
+			return nil, err
+		}
+		// Elements pay for their own bytes, the length only for the references
+		n, err := nr.readLength(4, minPayloadSize(TagType(elemType)))
+		if err != nil {
+			return nil, err
+		}
+		if TagType(elemType) == TagEnd && n > 0 {
+			return nil, fmt.Errorf("%w: non-empty list of %s", ErrNBTListType, TagEnd)
+		}
+		// Grown as elements arrive rather than sized by the claimed length
+		list := &ListTag{ElemType: TagType(elemType)}
+		for i := 0; i < n; i++ {
+			elem, err := nr.readPayload(TagType(elemType), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list.Elems = append(list.Elems, elem)
+		}
+		return list, nil
+	case TagCompound:
+		compound := make(CompoundTag)
+		for {
+			childType, err := nr.readByte()
+			if err != nil {
+				return nil, err
+			}
+			if TagType(childType) == TagEnd {
+				return compound, nil
+			}
+			name, err := nr.readString()
+			if err != nil {
+				return nil, err
+			}
+			child, err := nr.readPayload(TagType(childType), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			compound[name] = child
+		}
+	case TagIntArray:
+		n, err := nr.readLength(4, 4)
+		if err != nil {
+			return nil, err
+		}
+		data, err := nr.readArray(n, 4)
+		if err != nil {
+			return nil, err
+		}
+		values := make(IntArrayTag, n)
+		for i := range values {
+			values[i] = int32(binary.BigEndian.Uint32(data[4*i:]))
+		}
+		return values, nil
+	case TagLongArray:
+		n, err := nr.readLength(8, 8)
+		if err != nil {
+			return nil, err
+		}
+		data, err := nr.readArray(n, 8)
+		if err != nil {
+			return nil, err
+		}
+		values := make(LongArrayTag, n)
+		for i := range values {
+			values[i] = int64(binary.BigEndian.Uint64(data[8*i:]))
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrNBTUnknownTag, byte(typ))
+	}
+}
+
+// EncodeNBTFile writes a named root tag to w using the given compression.
+func EncodeNBTFile(w io.Writer, name string, tag Tag, compression NBTCompression) error {
+	switch compression {
+	case NBTGzip:
+		zw := gzip.NewWriter(w)
+		if err := NewNBTWriter(zw).WriteNamed(name, tag); err != nil {
+			return err
+		}
+		return zw.Close()
+	case NBTZlib:
+		zw := zlib.NewWriter(w)
+		if err := NewNBTWriter(zw).WriteNamed(name, tag); err != nil {
+			return err
+		}
+		return zw.Close()
+	default:
+		return NewNBTWriter(w).WriteNamed(name, tag)
+	}
+}
+
+// DecodeNBTFile reads a named root tag from r, detecting gzip or zlib compression.
+func DecodeNBTFile(r io.Reader) (string, Tag, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var src io.Reader = br
+	switch {
+	case header[0] == 0x1f && header[1] == 0x8b:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return "", nil, err
+		}
+		defer zr.Close()
+		src = zr
+	case header[0] == 0x78 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0:
+		zr, err := zlib.NewReader(br)
+		if err != nil {
+			return "", nil, err
+		}
+		defer zr.Close()
+		src = zr
+	}
+
+	return NewNBTReader(src, 0).ReadNamed()
+}
+
+// encodeModifiedUTF8 converts s to Java's modified UTF-8, as used by NBT strings.
+func encodeModifiedUTF8(s string) []byte {
+	plain := true
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 || s[i] >= utf8.RuneSelf {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return []byte(s)
+	}
+
+	out := make([]byte, 0, len(s)+4)
+	for _, r := range s {
+		if r >= 0x10000 {
+			hi, lo := utf16.EncodeRune(r)
+			out = appendModifiedUTF8Unit(out, uint16(hi))
+			out = appendModifiedUTF8Unit(out, uint16(lo))
+			continue
+		}
+		out = appendModifiedUTF8Unit(out, uint16(r))
+	}
+	return out
+}
+
+func appendModifiedUTF8Unit(out []byte, c uint16) []byte {
+	switch {
+	case c != 0 && c < 0x80:
+		return append(out, byte(c))
+	case c < 0x800:
+		return append(out, byte(0xC0|c>>6), byte(0x80|c&0x3F))
+	default:
+		return append(out, byte(0xE0|c>>12), byte(0x80|(c>>6)&0x3F), byte(0x80|c&0x3F))
+	}
+}
+
+// decodeModifiedUTF8 converts Java's modified UTF-8 back to a Go string.
+func decodeModifiedUTF8(data []byte) (string, error) {
+	plain := true
+	for _, b := range data {
+		if b == 0 || b >= utf8.RuneSelf {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return string(data), nil
+	}
+
+	units := make([]uint16, 0, len(data))
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b < 0x80 && b != 0:
+			units = append(units, uint16(b))
+			i++
+		case b&0xE0 == 0xC0:
+			if i+1 >= len(data) || data[i+1]&0xC0 != 0x80 {
+				return "", ErrNBTInvalidUTF
+			}
+			units = append(units, uint16(b&0x1F)<<6|uint16(data[i+1]&0x3F))
+			i += 2
+		case b&0xF0 == 0xE0:
+			if i+2 >= len(data) || data[i+1]&0xC0 != 0x80 || data[i+2]&0xC0 != 0x80 {
+				return "", ErrNBTInvalidUTF
+			}
+			units = append(units, uint16(b&0x0F)<<12|uint16(data[i+1]&0x3F)<<6|uint16(data[i+2]&0x3F))
+			i += 3
+		default:
+			return "", ErrNBTInvalidUTF
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
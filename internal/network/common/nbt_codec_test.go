@@ -0,0 +1,343 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// nestedLists returns a network root of depth lists, each holding the next.
+func nestedLists(depth int) []byte {
+	data := []byte{byte(TagList)}
+	for i := 0; i < depth; i++ {
+		data = append(data, byte(TagList), 0, 0, 0, 1)
+	}
+	return append(data, byte(TagEnd), 0, 0, 0, 0)
+}
+
+func TestNBTRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  Tag
+		want Tag    // Defaults to tag
+		wire []byte // Checked when set
+	}{
+		{name: "nil root", tag: nil, wire: []byte{0x00}},
+		{name: "byte", tag: ByteTag(-2), wire: []byte{0x01, 0xFE}},
+		{name: "short", tag: ShortTag(-300), wire: []byte{0x02, 0xFE, 0xD4}},
+		{name: "int", tag: IntTag(1), wire: []byte{0x03, 0x00, 0x00, 0x00, 0x01}},
+		{name: "long", tag: LongTag(math.MinInt64), wire: []byte{0x04, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "float", tag: FloatTag(1.5), wire: []byte{0x05, 0x3F, 0xC0, 0x00, 0x00}},
+		{name: "double", tag: DoubleTag(-0.25), wire: []byte{0x06, 0xBF, 0xD0, 0, 0, 0, 0, 0, 0}},
+		{name: "byte array", tag: ByteArrayTag{1, 2, 0xFF}, wire: []byte{0x07, 0, 0, 0, 3, 1, 2, 0xFF}},
+		{name: "empty byte array", tag: ByteArrayTag{}},
+		{name: "string", tag: StringTag("hi"), wire: []byte{0x08, 0x00, 0x02, 'h', 'i'}},
+		{name: "empty string", tag: StringTag(""), wire: []byte{0x08, 0x00, 0x00}},
+		{
+			name: "list",
+			tag:  NewListTag(IntTag(1), IntTag(2)),
+			wire: []byte{0x09, 0x03, 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 2},
+		},
+		{
+			name: "empty list is written as a list of TAG_End",
+			tag:  &ListTag{ElemType: TagInt},
+			want: &ListTag{ElemType: TagEnd},
+			wire: []byte{0x09, 0x00, 0, 0, 0, 0},
+		},
+		{
+			name: "list of lists",
+			tag:  NewListTag(NewListTag(StringTag("a")), NewListTag(StringTag("b"), StringTag("c"))),
+		},
+		{
+			name: "list of compounds",
+			tag:  NewListTag(CompoundTag{"id": StringTag("stone")}, CompoundTag{}),
+		},
+		{
+			name: "compound with a nameless root",
+			tag:  CompoundTag{"a": ByteTag(1)},
+			wire: []byte{0x0A, 0x01, 0x00, 0x01, 'a', 0x01, 0x00},
+		},
+		{
+			name: "compound keys are sorted",
+			tag:  CompoundTag{"b": ByteTag(2), "a": ByteTag(1)},
+			wire: []byte{0x0A, 0x01, 0x00, 0x01, 'a', 0x01, 0x01, 0x00, 0x01, 'b', 0x02, 0x00},
+		},
+		{
+			name: "compound drops nil children",
+			tag:  CompoundTag{"a": nil, "b": ShortTag(7)},
+			want: CompoundTag{"b": ShortTag(7)},
+		},
+		{name: "empty compound", tag: CompoundTag{}, wire: []byte{0x0A, 0x00}},
+		{
+			name: "nested compound",
+			tag: CompoundTag{
+				"name":  StringTag("Steve"),
+				"pos":   NewListTag(DoubleTag(1), DoubleTag(64), DoubleTag(-3)),
+				"inner": CompoundTag{"flag": BoolTag(true), "ids": IntArrayTag{4}},
+			},
+		},
+		{name: "int array", tag: IntArrayTag{-1, 0, math.MaxInt32}, wire: []byte{
+			0x0B, 0, 0, 0, 3, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0, 0x7F, 0xFF, 0xFF, 0xFF,
+		}},
+		{name: "empty int array", tag: IntArrayTag{}},
+		{name: "long array", tag: LongArrayTag{1, -1}, wire: []byte{
+			0x0C, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF,
+		}},
+		{name: "depth at the limit", tag: deepList(MaxNBTDepth)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == nil {
+				want = tt.tag
+			}
+
+			buf := NewBuffer(nil)
+			if err := buf.WriteNBT(tt.tag); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if tt.wire != nil && !bytes.Equal(buf.Bytes(), tt.wire) {
+				t.Errorf("encoded % X, want % X", buf.Bytes(), tt.wire)
+			}
+			wire := append([]byte(nil), buf.Bytes()...)
+
+			got, err := buf.ReadNBT()
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %#v, want %#v", got, want)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("%d bytes left after reading", buf.Len())
+			}
+
+			// Readers that can't tell how much input is left take another path for arrays
+			got, err = NewNBTReader(struct{ io.Reader }{bytes.NewReader(wire)}, 0).ReadNetwork()
+			if err != nil {
+				t.Fatalf("read without a known size: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("read without a known size: got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+// deepList returns a list nested depth levels below the root.
+func deepList(depth int) Tag {
+	tag := Tag(&ListTag{ElemType: TagEnd})
+	for i := 0; i < depth; i++ {
+		tag = NewListTag(tag)
+	}
+	return tag
+}
+
+func TestNBTFileRoundTrip(t *testing.T) {
+	tag := CompoundTag{"Data": CompoundTag{"LevelName": StringTag("world"), "Time": LongTag(24000)}}
+
+	for _, compression := range []NBTCompression{NBTUncompressed, NBTGzip, NBTZlib} {
+		var buf bytes.Buffer
+		if err := EncodeNBTFile(&buf, "root", tag, compression); err != nil {
+			t.Fatalf("compression %d: encode: %v", compression, err)
+		}
+		if compression == NBTUncompressed && !bytes.HasPrefix(buf.Bytes(), []byte{0x0A, 0x00, 0x04, 'r', 'o', 'o', 't'}) {
+			t.Errorf("named root encoded as % X", buf.Bytes()[:7])
+		}
+
+		name, got, err := DecodeNBTFile(&buf)
+		if err != nil {
+			t.Fatalf("compression %d: decode: %v", compression, err)
+		}
+		if name != "root" {
+			t.Errorf("compression %d: root name %q, want %q", compression, name, "root")
+		}
+		if !reflect.DeepEqual(got, tag) {
+			t.Errorf("compression %d: got %#v, want %#v", compression, got, tag)
+		}
+	}
+}
+
+func TestModifiedUTF8(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		wire []byte
+	}{
+		{name: "empty", s: "", wire: []byte{}},
+		{name: "ASCII", s: "abc", wire: []byte("abc")},
+		{name: "NUL is two bytes", s: "\x00", wire: []byte{0xC0, 0x80}},
+		{name: "NUL between ASCII", s: "a\x00b", wire: []byte{'a', 0xC0, 0x80, 'b'}},
+		{name: "two-byte sequence", s: "é", wire: []byte{0xC3, 0xA9}},
+		{name: "three-byte sequence", s: "€", wire: []byte{0xE2, 0x82, 0xAC}},
+		{name: "supplementary character as a surrogate pair", s: "😀", wire: []byte{0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80}},
+		{name: "mixed", s: "x😀\x00€", wire: []byte{'x', 0xED, 0xA0, 0xBD, 0xED, 0xB8, 0x80, 0xC0, 0x80, 0xE2, 0x82, 0xAC}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encodeModifiedUTF8(tt.s); !bytes.Equal(got, tt.wire) {
+				t.Errorf("encoded % X, want % X", got, tt.wire)
+			}
+			got, err := decodeModifiedUTF8(tt.wire)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tt.s {
+				t.Errorf("decoded %q, want %q", got, tt.s)
+			}
+
+			// And as a string tag, where the length counts encoded bytes
+			buf := NewBuffer(nil)
+			if err := buf.WriteNBT(StringTag(tt.s)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if length := int(buf.Bytes()[1])<<8 | int(buf.Bytes()[2]); length != len(tt.wire) {
+				t.Errorf("string length %d, want %d", length, len(tt.wire))
+			}
+			tag, err := buf.ReadNBT()
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if tag != StringTag(tt.s) {
+				t.Errorf("read %#v, want %q", tag, tt.s)
+			}
+		})
+	}
+}
+
+func TestModifiedUTF8Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "raw NUL", data: []byte{'a', 0x00}},
+		{name: "truncated two-byte sequence", data: []byte{0xC3}},
+		{name: "truncated three-byte sequence", data: []byte{0xE2, 0x82}},
+		{name: "bad continuation byte", data: []byte{0xC3, 0x29}},
+		{name: "lone continuation byte", data: []byte{0x80}},
+		{name: "four-byte UTF-8 sequence", data: []byte{0xF0, 0x9F, 0x98, 0x80}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, err := decodeModifiedUTF8(tt.data); !errors.Is(err, ErrNBTInvalidUTF) {
+				t.Errorf("decoded %q with error %v, want %v", s, err, ErrNBTInvalidUTF)
+			}
+		})
+	}
+}
+
+func TestNBTReadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		quota   int64 // Defaults to MaxNetworkNBTBytes
+		wantErr error // Checked with errors.Is when set
+	}{
+		{name: "unknown root type", data: []byte{0x0D}, wantErr: ErrNBTUnknownTag},
+		{name: "unknown child type", data: []byte{0x0A, 0x0D, 0x00, 0x00}, wantErr: ErrNBTUnknownTag},
+		{name: "negative byte array length", data: []byte{0x07, 0xFF, 0xFF, 0xFF, 0xFF}, wantErr: ErrNegativeLength},
+		{name: "negative list length", data: []byte{0x09, 0x01, 0x80, 0, 0, 0}, wantErr: ErrNegativeLength},
+		{name: "byte array longer than the input", data: []byte{0x07, 0x00, 0x10, 0x00, 0x00, 1, 2}, wantErr: io.ErrUnexpectedEOF},
+		{name: "int array longer than the input", data: []byte{0x0B, 0x00, 0x00, 0x00, 0x02, 0, 0, 0, 1}, wantErr: io.ErrUnexpectedEOF},
+		{name: "list longer than the input", data: []byte{0x09, 0x0A, 0x00, 0x1F, 0x00, 0x00}, wantErr: io.ErrUnexpectedEOF},
+		{name: "non-empty list of TAG_End", data: []byte{0x09, 0x00, 0, 0, 0, 1, 0x00}, wantErr: ErrNBTListType},
+		{name: "malformed string", data: []byte{0x08, 0x00, 0x01, 0xC3}, wantErr: ErrNBTInvalidUTF},
+		{name: "truncated string", data: []byte{0x08, 0x00, 0x05, 'a'}},
+		{name: "unterminated compound", data: []byte{0x0A, 0x01, 0x00, 0x01, 'a', 0x01}},
+		{name: "empty input", data: []byte{}},
+		{name: "nested too deep", data: nestedLists(MaxNBTDepth + 1), wantErr: ErrNBTTooDeep},
+		{
+			name:    "string over the quota",
+			data:    []byte{0x08, 0x00, 0x0A, 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j'},
+			quota:   8,
+			wantErr: ErrNBTTooLarge,
+		},
+		{
+			name:    "byte array over the quota",
+			data:    append([]byte{0x07, 0x00, 0x00, 0x01, 0x00}, make([]byte, 256)...),
+			quota:   128,
+			wantErr: ErrNBTTooLarge,
+		},
+		{
+			// 1 root type + 1 element type + 4 length + 3 references of 4 + 3 elements
+			name:    "list references count against the quota",
+			data:    []byte{0x09, 0x01, 0, 0, 0, 3, 1, 2, 3},
+			quota:   20,
+			wantErr: ErrNBTTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota := tt.quota
+			if quota == 0 {
+				quota = MaxNetworkNBTBytes
+			}
+			tag, err := NewNBTReader(NewBuffer(tt.data), quota).ReadNetwork()
+			if err == nil {
+				t.Fatalf("read %#v", tag)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNBTQuotaBoundary(t *testing.T) {
+	// The list of TestNBTReadErrors costs exactly 21 bytes
+	data := []byte{0x09, 0x01, 0, 0, 0, 3, 1, 2, 3}
+	tag, err := NewNBTReader(NewBuffer(data), 21).ReadNetwork()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if want := NewListTag(ByteTag(1), ByteTag(2), ByteTag(3)); !reflect.DeepEqual(tag, want) {
+		t.Errorf("got %#v, want %#v", tag, want)
+	}
+}
+
+func TestNBTWriteErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     Tag
+		wantErr error
+	}{
+		{name: "string too long", tag: StringTag(strings.Repeat("a", math.MaxUint16+1)), wantErr: ErrNBTStringLength},
+		{name: "encoded string too long", tag: StringTag(strings.Repeat("\x00", math.MaxUint16/2+1)), wantErr: ErrNBTStringLength},
+		{name: "compound key too long", tag: CompoundTag{strings.Repeat("k", math.MaxUint16+1): ByteTag(0)}, wantErr: ErrNBTStringLength},
+		{name: "mixed list", tag: &ListTag{ElemType: TagInt, Elems: []Tag{IntTag(1), ShortTag(2)}}, wantErr: ErrNBTListType},
+		{name: "list with a nil element", tag: &ListTag{ElemType: TagInt, Elems: []Tag{nil}}, wantErr: ErrNBTListType},
+		{name: "nested too deep", tag: deepList(MaxNBTDepth + 1), wantErr: ErrNBTTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewBuffer(nil).WriteNBT(tt.tag); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListTagAdd(t *testing.T) {
+	list := &ListTag{}
+	if err := list.Add(StringTag("a")); err != nil {
+		t.Fatalf("first element: %v", err)
+	}
+	if list.ElemType != TagString {
+		t.Errorf("element type %s, want %s", list.ElemType, TagString)
+	}
+	if err := list.Add(IntTag(1)); !errors.Is(err, ErrNBTListType) {
+		t.Errorf("adding an int to a string list: got %v, want %v", err, ErrNBTListType)
+	}
+	if list.Len() != 1 {
+		t.Errorf("list has %d elements, want 1", list.Len())
+	}
+}
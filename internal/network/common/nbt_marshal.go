@@ -0,0 +1,348 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrNBTUnsupportedType is returned when a Go value has no NBT representation.
+var ErrNBTUnsupportedType = errors.New("nbt: unsupported Go type")
+
+var tagInterface = reflect.TypeOf((*Tag)(nil)).Elem()
+
+// MarshalNBT converts a Go value into a tag.
+//
+// Structs become compounds. Fields are named by the `nbt:"name"` struct tag, or by the
+// field name when absent; `nbt:"-"` skips a field and `,omitempty` drops zero values.
+// bool and int8 map to TAG_Byte, int16 to TAG_Short, int32 and int to TAG_Int, int64 to
+// TAG_Long, []byte to TAG_Byte_Array, []int32 to TAG_Int_Array and []int64 to TAG_Long_Array.
+// Other slices become lists, string-keyed maps become compounds, and Tag values pass through.
+func MarshalNBT(v any) (Tag, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return marshalValue(reflect.ValueOf(v))
+}
+
+func marshalValue(v reflect.Value) (Tag, error) {
+	if v.Type().Implements(tagInterface) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nil, nil
+		}
+		return v.Interface().(Tag), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return marshalValue(v.Elem())
+	case reflect.Bool:
+		return BoolTag(v.Bool()), nil
+	case reflect.Int8:
+		return ByteTag(v.Int()), nil
+	case reflect.Uint8:
+		return ByteTag(v.Uint()), nil
+	case reflect.Int16:
+		return ShortTag(v.Int()), nil
+	case reflect.Uint16:
+		return ShortTag(v.Uint()), nil
+	case reflect.Int32, reflect.Int:
+		return IntTag(v.Int()), nil
+	case reflect.Uint32:
+		return IntTag(v.Uint()), nil
+	case reflect.Int64:
+		return LongTag(v.Int()), nil
+	case reflect.Uint64:
+		return LongTag(v.Uint()), nil
+	case reflect.Float32:
+		return FloatTag(v.Float()), nil
+	case reflect.Float64:
+		return DoubleTag(v.Float()), nil
+	case reflect.String:
+		return StringTag(v.String()), nil
+	case reflect.Slice, reflect.Array:
+		return marshalSlice(v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: map key %s", ErrNBTUnsupportedType, v.Type().Key())
+		}
+		compound := make(CompoundTag, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			child, err := marshalValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			if child != nil {
+				compound[iter.Key().String()] = child
+			}
+		}
+		return compound, nil
+	case reflect.Struct:
+		return marshalStruct(v)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNBTUnsupportedType, v.Type())
+	}
+}
+
+func marshalSlice(v reflect.Value) (Tag, error) {
+	switch v.Type().Elem().Kind() {
+	case reflect.Uint8:
+		data := make(ByteArrayTag, v.Len())
+		for i := range data {
+			data[i] = byte(v.Index(i).Uint())
+		}
+		return data, nil
+	case reflect.Int8:
+		data := make(ByteArrayTag, v.Len())
+		for i := range data {
+			data[i] = byte(v.Index(i).Int())
+		}
+		return data, nil
+	case reflect.Int32:
+		data := make(IntArrayTag, v.Len())
+		for i := range data {
+			data[i] = int32(v.Index(i).Int())
+		}
+		return data, nil
+	case reflect.Int64:
+		data := make(LongArrayTag, v.Len())
+		for i := range data {
+			data[i] = v.Index(i).Int()
+		}
+		return data, nil
+	}
+
+	list := &ListTag{Elems: make([]Tag, 0, v.Len())}
+	for i := 0; i < v.Len(); i++ {
+		elem, err := marshalValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			continue
+		}
+		if err := list.Add(elem); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func marshalStruct(v reflect.Value) (Tag, error) {
+	compound := make(CompoundTag)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitEmpty, skip := parseNBTFieldTag(field)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		child, err := marshalValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if child != nil {
+			compound[name] = child
+		}
+	}
+	return compound, nil
+}
+
+func parseNBTFieldTag(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("nbt")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, opts == "omitempty", false
+}
+
+// UnmarshalNBT stores tag into the value pointed to by v, following the rules of MarshalNBT.
+// Numeric tags convert freely between widths; compound keys without a matching field are ignored.
+func UnmarshalNBT(tag Tag, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: UnmarshalNBT needs a non-nil pointer", ErrNBTUnsupportedType)
+	}
+	return unmarshalValue(tag, rv.Elem())
+}
+
+func unmarshalValue(tag Tag, v reflect.Value) error {
+	if tag == nil {
+		return nil
+	}
+
+	if v.Type().Implements(tagInterface) || v.Type() == tagInterface {
+		tv := reflect.ValueOf(tag)
+		if !tv.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("%w: cannot store %s in %s", ErrNBTUnsupportedType, tag.Type(), v.Type())
+		}
+		v.Set(tv)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(tag, v.Elem())
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(tag))
+			return nil
+		}
+	case reflect.Bool:
+		n, ok := tagInteger(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetBool(n != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := tagInteger(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := tagInteger(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := tagFloat(tag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetFloat(n)
+		return nil
+	case reflect.String:
+		s, ok := tag.(StringTag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		v.SetString(string(s))
+		return nil
+	case reflect.Slice:
+		return unmarshalSlice(tag, v)
+	case reflect.Map:
+		compound, ok := tag.(CompoundTag)
+		if !ok || v.Type().Key().Kind() != reflect.String {
+			return mismatch(tag, v)
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(compound)))
+		}
+		for key, child := range compound {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := unmarshalValue(child, elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), elem)
+		}
+		return nil
+	case reflect.Struct:
+		compound, ok := tag.(CompoundTag)
+		if !ok {
+			return mismatch(tag, v)
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, skip := parseNBTFieldTag(field)
+			if skip {
+				continue
+			}
+			child, ok := compound[name]
+			if !ok {
+				continue
+			}
+			if err := unmarshalValue(child, v.Field(i)); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		return nil
+	}
+	return mismatch(tag, v)
+}
+
+func unmarshalSlice(tag Tag, v reflect.Value) error {
+	switch t := tag.(type) {
+	case ByteArrayTag:
+		return fillSlice(v, len(t), func(i int) Tag { return ByteTag(t[i]) })
+	case IntArrayTag:
+		return fillSlice(v, len(t), func(i int) Tag { return IntTag(t[i]) })
+	case LongArrayTag:
+		return fillSlice(v, len(t), func(i int) Tag { return LongTag(t[i]) })
+	case *ListTag:
+		return fillSlice(v, len(t.Elems), func(i int) Tag { return t.Elems[i] })
+	default:
+		return mismatch(tag, v)
+	}
+}
+
+func fillSlice(v reflect.Value, n int, at func(int) Tag) error {
+	out := reflect.MakeSlice(v.Type(), n, n)
+	for i := 0; i < n; i++ {
+		if err := unmarshalValue(at(i), out.Index(i)); err != nil {
+			return err
+		}
+	}
+	v.Set(out)
+	return nil
+}
+
+func tagInteger(tag Tag) (int64, bool) {
+	switch t := tag.(type) {
+	case ByteTag:
+		return int64(t), true
+	case ShortTag:
+		return int64(t), true
+	case IntTag:
+		return int64(t), true
+	case LongTag:
+		return int64(t), true
+	case FloatTag:
+		return int64(t), true
+	case DoubleTag:
+		return int64(t), true
+	}
+	return 0, false
+}
+
+func tagFloat(tag Tag) (float64, bool) {
+	switch t := tag.(type) {
+	case FloatTag:
+		return float64(t), true
+	case DoubleTag:
+		return float64(t), true
+	}
+	n, ok := tagInteger(tag)
+	return float64(n), ok
+}
+
+func mismatch(tag Tag, v reflect.Value) error {
+	return fmt.Errorf("%w: cannot store %s in %s", ErrNBTUnsupportedType, tag.Type(), v.Type())
+}
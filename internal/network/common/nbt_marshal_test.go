@@ -0,0 +1,241 @@
+package common
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testItem struct {
+	ID    string `nbt:"id"`
+	Count int8   `nbt:"count"`
+}
+
+type testEntity struct {
+	Name      string           `nbt:"name"`
+	Health    float32          `nbt:"health"`
+	OnGround  bool             `nbt:"on_ground"`
+	Age       int64            // Named after the field
+	Pos       []float64        `nbt:"pos"`
+	UUID      []int32          `nbt:"uuid"`
+	Data      []byte           `nbt:"data,omitempty"`
+	Items     []testItem       `nbt:"items,omitempty"`
+	Owner     *string          `nbt:"owner,omitempty"`
+	FixedTime *int64           `nbt:"fixed_time,omitempty"`
+	Extra     map[string]int32 `nbt:"extra,omitempty"`
+	Raw       Tag              `nbt:"raw,omitempty"`
+	Ignored   string           `nbt:"-"`
+	hidden    int
+}
+
+func TestMarshalNBT(t *testing.T) {
+	owner := "Alex"
+	noon := int64(6000)
+
+	tests := []struct {
+		name string
+		in   any
+		want Tag
+	}{
+		{name: "nil", in: nil, want: nil},
+		{name: "Tag passes through", in: IntTag(3), want: IntTag(3)},
+		{name: "bool", in: true, want: ByteTag(1)},
+		{name: "int16", in: int16(-2), want: ShortTag(-2)},
+		{name: "int", in: 7, want: IntTag(7)},
+		{name: "float64", in: 0.5, want: DoubleTag(0.5)},
+		{name: "byte slice", in: []byte{1, 2}, want: ByteArrayTag{1, 2}},
+		{name: "int64 slice", in: []int64{9}, want: LongArrayTag{9}},
+		{name: "string slice", in: []string{"a", "b"}, want: NewListTag(StringTag("a"), StringTag("b"))},
+		{name: "map", in: map[string]bool{"x": false}, want: CompoundTag{"x": ByteTag(0)}},
+		{
+			name: "struct with omitted fields",
+			in:   testEntity{Name: "Steve", Ignored: "skipped", hidden: 1},
+			want: CompoundTag{
+				"name":      StringTag("Steve"),
+				"health":    FloatTag(0),
+				"on_ground": ByteTag(0),
+				"Age":       LongTag(0),
+				"pos":       &ListTag{Elems: []Tag{}},
+				"uuid":      IntArrayTag{},
+			},
+		},
+		{
+			name: "struct with every field",
+			in: &testEntity{
+				Name:      "Steve",
+				Health:    20,
+				OnGround:  true,
+				Age:       100,
+				Pos:       []float64{1, 2, 3},
+				UUID:      []int32{1, 2, 3, 4},
+				Data:      []byte{0xAB},
+				Items:     []testItem{{ID: "minecraft:stone", Count: 64}},
+				Owner:     &owner,
+				FixedTime: &noon,
+				Extra:     map[string]int32{"level": 3},
+				Raw:       CompoundTag{"custom": StringTag("x")},
+			},
+			want: CompoundTag{
+				"name":       StringTag("Steve"),
+				"health":     FloatTag(20),
+				"on_ground":  ByteTag(1),
+				"Age":        LongTag(100),
+				"pos":        NewListTag(DoubleTag(1), DoubleTag(2), DoubleTag(3)),
+				"uuid":       IntArrayTag{1, 2, 3, 4},
+				"data":       ByteArrayTag{0xAB},
+				"items":      NewListTag(CompoundTag{"id": StringTag("minecraft:stone"), "count": ByteTag(64)}),
+				"owner":      StringTag("Alex"),
+				"fixed_time": LongTag(6000),
+				"extra":      CompoundTag{"level": IntTag(3)},
+				"raw":        CompoundTag{"custom": StringTag("x")},
+			},
+		},
+		{
+			name: "nil pointers are left out even without omitempty",
+			in: struct {
+				A *int32 `nbt:"a"`
+				B *int32 `nbt:"b"`
+			}{B: new(int32)},
+			want: CompoundTag{"b": IntTag(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalNBT(tt.in)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalNBTUnsupported(t *testing.T) {
+	for _, in := range []any{make(chan int), map[int]string{1: "a"}, struct{ F func() }{F: func() {}}, []any{IntTag(1), StringTag("a")}} {
+		if _, err := MarshalNBT(in); err == nil {
+			t.Errorf("marshalled %T", in)
+		}
+	}
+	if _, err := MarshalNBT(map[int]string{1: "a"}); !errors.Is(err, ErrNBTUnsupportedType) {
+		t.Errorf("got error %v, want %v", err, ErrNBTUnsupportedType)
+	}
+}
+
+func TestNBTValueRoundTrip(t *testing.T) {
+	owner := "Alex"
+	noon := int64(6000)
+
+	tests := []struct {
+		name string
+		in   testEntity
+	}{
+		{
+			name: "every field",
+			in: testEntity{
+				Name:      "Steve",
+				Health:    19.5,
+				OnGround:  true,
+				Age:       -1,
+				Pos:       []float64{0.5, 70, -12.25},
+				UUID:      []int32{-1, 0, 1, 2},
+				Data:      []byte{0, 1},
+				Items:     []testItem{{ID: "minecraft:dirt", Count: 1}, {ID: "minecraft:stone", Count: -1}},
+				Owner:     &owner,
+				FixedTime: &noon,
+				Extra:     map[string]int32{"a": 1, "b": 2},
+				Raw:       NewListTag(IntTag(5)),
+			},
+		},
+		{
+			name: "optional fields absent",
+			in:   testEntity{Name: "Alex", Pos: []float64{}, UUID: []int32{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := NewBuffer(nil)
+			if err := buf.WriteNBTValue(tt.in); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			var got testEntity
+			if err := buf.ReadNBTValue(&got); err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.in) {
+				t.Errorf("got %+v, want %+v", got, tt.in)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("%d bytes left after reading", buf.Len())
+			}
+		})
+	}
+}
+
+func TestUnmarshalNBT(t *testing.T) {
+	t.Run("numbers convert between widths", func(t *testing.T) {
+		var got struct {
+			A int64   `nbt:"a"`
+			B bool    `nbt:"b"`
+			C float64 `nbt:"c"`
+			D uint8   `nbt:"d"`
+		}
+		tag := CompoundTag{"a": ByteTag(-3), "b": IntTag(2), "c": IntTag(4), "d": ShortTag(200)}
+		if err := UnmarshalNBT(tag, &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if got.A != -3 || !got.B || got.C != 4 || got.D != 200 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("missing and unknown keys", func(t *testing.T) {
+		fixed := int64(1)
+		got := testEntity{Name: "kept", FixedTime: &fixed}
+		if err := UnmarshalNBT(CompoundTag{"unknown": StringTag("x"), "health": FloatTag(2)}, &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if got.Name != "kept" || got.FixedTime != &fixed || got.Health != 2 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("nil tag leaves the value alone", func(t *testing.T) {
+		got := 5
+		if err := UnmarshalNBT(nil, &got); err != nil || got != 5 {
+			t.Errorf("got %d, %v", got, err)
+		}
+	})
+
+	t.Run("into any", func(t *testing.T) {
+		var got any
+		if err := UnmarshalNBT(StringTag("a"), &got); err != nil || got != StringTag("a") {
+			t.Errorf("got %#v, %v", got, err)
+		}
+	})
+
+	errorTests := []struct {
+		name string
+		tag  Tag
+		v    any
+	}{
+		{name: "string into int", tag: StringTag("1"), v: new(int)},
+		{name: "int into string", tag: IntTag(1), v: new(string)},
+		{name: "list into struct", tag: NewListTag(IntTag(1)), v: new(testItem)},
+		{name: "compound into slice", tag: CompoundTag{}, v: new([]int32)},
+		{name: "mismatched field", tag: CompoundTag{"count": StringTag("many")}, v: new(testItem)},
+		{name: "wrong tag type for a Tag field", tag: IntTag(1), v: new(StringTag)},
+		{name: "not a pointer", tag: IntTag(1), v: 0},
+		{name: "nil pointer", tag: IntTag(1), v: (*int)(nil)},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UnmarshalNBT(tt.tag, tt.v); !errors.Is(err, ErrNBTUnsupportedType) {
+				t.Errorf("got error %v, want %v", err, ErrNBTUnsupportedType)
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package common
 
+import "fmt"
+
 // ConnectionState represents the connection state of a client.
 type ConnectionState int
 
@@ -11,14 +13,33 @@ const (
 	Play                                 // Client (re-)finished configuration.
 )
 
+func (s ConnectionState) String() string {
+	switch s {
+	case Handshake:
+		return "Handshake"
+	case Status:
+		return "Status"
+	case Login:
+		return "Login"
+	case Configuration:
+		return "Configuration"
+	case Play:
+		return "Play"
+	}
+	return fmt.Sprintf("ConnectionState(%d)", int(s))
+}
+
 type Packet interface {
 	ID() int32
 }
 
+// ServerboundPacket is a packet decoded from the client. Handling is registered separately,
+// so decoding never depends on server state.
 type ServerboundPacket interface {
 	Packet
-	Read(buf *Buffer)
-	Handle(pc *PlayerConnection)
+	// Read decodes the packet from buf, which holds exactly its payload. It returns an error
+	// if the payload is malformed.
+	Read(buf *Buffer) error
 }
 
 type ClientboundPacket interface {
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// OutboundQueueSize is how many packets may wait for the writer before the client is
+	// considered too slow and disconnected.
+	OutboundQueueSize = 4096
+	// WriteTimeout bounds a single write to the client.
+	WriteTimeout = 30 * time.Second
+
+	maxBatchSize      = 64 * 1024 // Batches are written early once they grow past this
+	maxPooledCapacity = 64 * 1024 // Larger buffers are left to the garbage collector
+)
+
+var (
+	ErrConnectionClosed = errors.New("connection is closed")
+	ErrSlowConsumer     = errors.New("outbound queue is full")
+)
+
+var bufferPool = sync.Pool{
+	New: func() any { return NewBuffer(nil) },
+}
+
+func getBuffer() *Buffer {
+	buf := bufferPool.Get().(*Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *Buffer) {
+	if buf.Cap() <= maxPooledCapacity {
+		bufferPool.Put(buf)
+	}
+}
+
+// outboundFrame is a framed packet waiting for the writer. It remembers the connection it
+// was encoded for, so frames queued before encryption was enabled are sent unencrypted.
+type outboundFrame struct {
+	conn net.Conn
+	buf  *Buffer
+}
+
+// writer sends queued frames from its own goroutine. Frames are collected into a batch that
+// is written on Flush, when it grows past maxBatchSize, or when the connection closes.
+type writer struct {
+	queue   chan outboundFrame
+	flush   chan struct{}
+	closing chan struct{}
+	done    chan struct{}
+
+	closeOnce sync.Once
+}
+
+func newWriter() *writer {
+	return &writer{
+		queue:   make(chan outboundFrame, OutboundQueueSize),
+		flush:   make(chan struct{}, 1),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// enqueue hands a frame to the writer without blocking. It fails with ErrSlowConsumer
+// if the queue is full.
+func (w *writer) enqueue(frame outboundFrame) error {
+	select {
+	case w.queue <- frame:
+		return nil
+	default:
+		putBuffer(frame.buf)
+		return ErrSlowConsumer
+	}
+}
+
+// requestFlush asks the writer to send everything queued so far.
+func (w *writer) requestFlush() {
+	select {
+	case w.flush <- struct{}{}:
+	default:
+	}
+}
+
+// stop makes the writer send what is queued, close conn and exit.
+func (w *writer) stop() {
+	w.closeOnce.Do(func() { close(w.closing) })
+}
+
+// run writes frames until stopped or a write fails, then closes conn.
+func (w *writer) run(conn net.Conn, logger *log.Logger) {
+	defer close(w.done)
+	defer conn.Close()
+
+	var batch bytes.Buffer
+	var batchConn net.Conn
+	var closeDeadline time.Time // Once closing, everything left must be written by then
+
+	write := func() bool {
+		if batch.Len() == 0 {
+			return true
+		}
+		defer batch.Reset()
+
+		deadline := closeDeadline
+		if deadline.IsZero() {
+			deadline = time.Now().Add(WriteTimeout)
+		}
+		if err := batchConn.SetWriteDeadline(deadline); err != nil {
+			return false
+		}
+		if _, err := batchConn.Write(batch.Bytes()); err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Printf("Failed to write to client: %v", err)
+			}
+			return false
+		}
+		return true
+	}
+	add := func(frame outboundFrame) bool {
+		defer putBuffer(frame.buf)
+		if frame.conn != batchConn {
+			if !write() {
+				return false
+			}
+			batchConn = frame.conn
+		}
+		batch.Write(frame.buf.Bytes())
+		return batch.Len() < maxBatchSize || write()
+	}
+	drain := func() bool {
+		for {
+			select {
+			case frame := <-w.queue:
+				if !add(frame) {
+					return false
+				}
+			default:
+				return true
+			}
+		}
+	}
+
+	for {
+		select {
+		case frame := <-w.queue:
+			if !add(frame) {
+				return
+			}
+		case <-w.flush:
+			if !drain() || !write() {
+				return
+			}
+		case <-w.closing:
+			closeDeadline = time.Now().Add(WriteTimeout)
+			if drain() {
+				write()
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"Veloce/internal/network/common"
+	"Veloce/internal/objects/optional"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net"
+	"strings"
+)
+
+// ErrNoForwardingData is returned for handshakes that did not come through a forwarding proxy.
+var ErrNoForwardingData = errors.New("handshake carries no forwarding data")
+
+type bungeeCordProperty struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Signature string `json:"signature"`
+}
+
+// ParseBungeeCordAddress decodes the handshake server address of BungeeCord legacy forwarding,
+// "host\x00ip\x00uuid\x00properties", where the UUID has no dashes and the optional properties
+// are a JSON array. It returns the host the client connected to and the forwarded player, whose
+// profile has no name; that comes with Login Start.
+//
+// Anyone can send such a handshake, so this must only be enabled if the server is unreachable
+// except through the proxy.
+func ParseBungeeCordAddress(address string) (string, ForwardedPlayer, error) {
+	parts := strings.Split(address, "\x00")
+	if len(parts) != 3 && len(parts) != 4 {
+		return "", ForwardedPlayer{}, ErrNoForwardingData
+	}
+
+	ip := net.ParseIP(parts[1])
+	if ip == nil {
+		return "", ForwardedPlayer{}, fmt.Errorf("invalid forwarded address %q", parts[1])
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return "", ForwardedPlayer{}, fmt.Errorf("invalid forwarded UUID %q: %w", parts[2], err)
+	}
+
+	profile := common.GameProfile{UUID: id}
+	if len(parts) == 4 {
+		var properties []bungeeCordProperty
+		if err := json.Unmarshal([]byte(parts[3]), &properties); err != nil {
+			return "", ForwardedPlayer{}, fmt.Errorf("invalid forwarded properties: %w", err)
+		}
+		for _, p := range properties {
+			property := common.Property{Name: p.Name, Value: p.Value}
+			if p.Signature != "" {
+				property.Signature = *optional.Of(p.Signature)
+			}
+			profile.Properties = append(profile.Properties, property)
+		}
+	}
+	return parts[0], ForwardedPlayer{Address: ip, Profile: profile}, nil
+}
@@ -0,0 +1,195 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// The PROXY protocol lets a TCP load balancer prefix each connection with the client's
+// real address. See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+
+var (
+	ErrInvalidProxyHeader = errors.New("invalid PROXY protocol header")
+
+	proxyV1Prefix    = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+const (
+	maxProxyV1Length = 107
+
+	proxyV2Local = 0x20
+	proxyV2Proxy = 0x21
+
+	proxyV2TCP4 = 0x11
+	proxyV2TCP6 = 0x21
+)
+
+// proxiedConn reports the address from the PROXY header and reads through the buffered
+// reader that consumed the header.
+type proxiedConn struct {
+	net.Conn
+	reader     *bufio.Reader
+	remoteAddr net.Addr
+}
+
+func (c *proxiedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c *proxiedConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// AcceptProxyHeader reads a PROXY protocol v1 or v2 header from conn. The returned connection
+// reports the client address from the header as its RemoteAddr; headers for health checks
+// (LOCAL, UNKNOWN) keep the load balancer's address.
+func AcceptProxyHeader(conn net.Conn) (net.Conn, error) {
+	reader := bufio.NewReader(conn)
+
+	signature, err := reader.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProxyHeader, err)
+	}
+
+	var addr net.Addr
+	switch {
+	case bytes.Equal(signature, proxyV2Signature):
+		addr, err = readProxyV2(reader)
+	case bytes.HasPrefix(signature, proxyV1Prefix):
+		addr, err = readProxyV1(reader)
+	default:
+		err = ErrInvalidProxyHeader
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if addr == nil {
+		addr = conn.RemoteAddr()
+	}
+	return &proxiedConn{Conn: conn, reader: reader, remoteAddr: addr}, nil
+}
+
+// readProxyV1 reads "PROXY TCP4 <src> <dst> <sport> <dport>\r\n".
+func readProxyV1(reader *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= maxProxyV1Length {
+			return nil, fmt.Errorf("%w: line too long", ErrInvalidProxyHeader)
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidProxyHeader, err)
+		}
+		line = append(line, b)
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidProxyHeader, line)
+	}
+
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidProxyHeader, line)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyV2 reads the binary header: signature, version and command, family, length and
+// the addresses.
+func readProxyV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, len(proxyV2Signature)+4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProxyHeader, err)
+	}
+	command := header[12]
+	family := header[13]
+	length := binary.BigEndian.Uint16(header[14:])
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProxyHeader, err)
+	}
+
+	switch command {
+	case proxyV2Local:
+		return nil, nil
+	case proxyV2Proxy:
+	default:
+		return nil, fmt.Errorf("%w: unknown command 0x%02X", ErrInvalidProxyHeader, command)
+	}
+
+	switch family {
+	case proxyV2TCP4:
+		if len(body) < 12 {
+			return nil, fmt.Errorf("%w: short IPv4 addresses", ErrInvalidProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(body[0:4]), Port: int(binary.BigEndian.Uint16(body[8:]))}, nil
+	case proxyV2TCP6:
+		if len(body) < 36 {
+			return nil, fmt.Errorf("%w: short IPv6 addresses", ErrInvalidProxyHeader)
+		}
+		return &net.TCPAddr{IP: net.IP(body[0:16]), Port: int(binary.BigEndian.Uint16(body[32:]))}, nil
+	}
+	// UDP and Unix sockets carry no address a player could have
+	return nil, nil
+}
+
+// TrustedProxies is an allowlist of addresses that may send PROXY headers.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses IP addresses and CIDR ranges.
+func ParseTrustedProxies(entries ...string) (TrustedProxies, error) {
+	trusted := make(TrustedProxies, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		trusted = append(trusted, network)
+	}
+	return trusted, nil
+}
+
+// Contains reports whether addr belongs to a trusted proxy. An empty list trusts everyone.
+func (t TrustedProxies) Contains(addr net.Addr) bool {
+	if len(t) == 0 {
+		return true
+	}
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, network := range t {
+		if network.Contains(tcp.IP) {
+			return true
+		}
+	}
+	return false
+}
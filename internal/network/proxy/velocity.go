@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"Veloce/internal/network/common"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	// VelocityChannel is the login plugin channel Velocity answers with the player's info.
+	VelocityChannel = "velocity:player_info"
+	// VelocityForwardingVersion is the forwarding version requested, MODERN_DEFAULT, which
+	// carries no chat signing key.
+	VelocityForwardingVersion = 1
+)
+
+var (
+	ErrInvalidSignature   = errors.New("forwarded player info has an invalid signature")
+	ErrUnsupportedVersion = errors.New("unsupported forwarding version")
+)
+
+// ForwardedPlayer is what a proxy tells the server about the player behind it.
+type ForwardedPlayer struct {
+	Address net.IP
+	Profile common.GameProfile
+}
+
+// VelocityRequest returns the data of the Login Plugin Request asking Velocity for player info.
+func VelocityRequest() []byte {
+	return []byte{VelocityForwardingVersion}
+}
+
+// ReadVelocityResponse verifies and decodes Velocity's answer on VelocityChannel. The
+// payload is signed with HMAC-SHA256 using the forwarding secret shared with the proxy.
+func ReadVelocityResponse(data, secret []byte) (ForwardedPlayer, error) {
+	if len(data) < sha256.Size {
+		return ForwardedPlayer{}, ErrInvalidSignature
+	}
+	signature, payload := data[:sha256.Size], data[sha256.Size:]
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ForwardedPlayer{}, ErrInvalidSignature
+	}
+
+	buf := common.NewBuffer(payload)
+	version, err := buf.ReadVarInt()
+	if err != nil {
+		return ForwardedPlayer{}, err
+	}
+	// Velocity never answers with a newer version than requested
+	if version != VelocityForwardingVersion {
+		return ForwardedPlayer{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	address, err := buf.ReadString()
+	if err != nil {
+		return ForwardedPlayer{}, err
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ForwardedPlayer{}, fmt.Errorf("invalid forwarded address %q", address)
+	}
+
+	profile, err := buf.ReadGameProfile()
+	if err != nil {
+		return ForwardedPlayer{}, err
+	}
+	return ForwardedPlayer{Address: ip, Profile: profile}, nil
+}
@@ -2,17 +2,45 @@ package network
 
 import (
 	"Veloce/internal/network/common"
+	"fmt"
+	"reflect"
+	"sort"
 	"sync"
 )
 
+// Direction tells which side sends a packet.
+type Direction int
+
+const (
+	ServerBound Direction = iota // Sent by the client
+	ClientBound                  // Sent by the server
+)
+
+func (d Direction) String() string {
+	if d == ServerBound {
+		return "serverbound"
+	}
+	return "clientbound"
+}
+
+// PacketInfo describes a registered packet.
+type PacketInfo struct {
+	Direction Direction
+	State     common.ConnectionState
+	ID        int32
+	Type      reflect.Type
+}
+
 type PacketRegistry struct {
 	mu          sync.RWMutex
 	serverBound map[common.ConnectionState]map[int32]func() common.ServerboundPacket
+	clientBound map[common.ConnectionState]map[reflect.Type]int32
 }
 
 func NewPacketRegistry() *PacketRegistry {
 	return &PacketRegistry{
 		serverBound: make(map[common.ConnectionState]map[int32]func() common.ServerboundPacket),
+		clientBound: make(map[common.ConnectionState]map[reflect.Type]int32),
 	}
 }
 
@@ -27,6 +55,18 @@ func (r *PacketRegistry) RegisterServerBound(state common.ConnectionState, id in
 	r.serverBound[state][id] = factory
 }
 
+// RegisterClientBound registers a packet type we may send to clients in state. The factory
+// only serves to learn the type; a type may be registered in several states.
+func (r *PacketRegistry) RegisterClientBound(state common.ConnectionState, id int32, factory func() common.ClientboundPacket) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.clientBound[state] == nil {
+		r.clientBound[state] = make(map[reflect.Type]int32)
+	}
+	r.clientBound[state][reflect.TypeOf(factory())] = id
+}
+
 // CreateServerBound creates a new server-bound packet instance
 func (r *PacketRegistry) CreateServerBound(state common.ConnectionState, id int32) (common.ServerboundPacket, bool) {
 	r.mu.RLock()
@@ -47,3 +87,53 @@ func (r *PacketRegistry) CreateServerBound(state common.ConnectionState, id int3
 func (r *PacketRegistry) GetServerBoundPacket(state common.ConnectionState, id int32) (common.ServerboundPacket, bool) {
 	return r.CreateServerBound(state, id)
 }
+
+// ClientBoundID returns the ID a clientbound packet type is registered with in state.
+func (r *PacketRegistry) ClientBoundID(state common.ConnectionState, packetType reflect.Type) (int32, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id, ok := r.clientBound[state][packetType]
+	return id, ok
+}
+
+// ValidateClientBound returns an error unless p is registered for state with the ID it reports.
+func (r *PacketRegistry) ValidateClientBound(state common.ConnectionState, p common.ClientboundPacket) error {
+	id, ok := r.ClientBoundID(state, reflect.TypeOf(p))
+	if !ok {
+		return fmt.Errorf("%T is not a clientbound packet of state %s", p, state)
+	}
+	if p.ID() != id {
+		return fmt.Errorf("%T has ID 0x%02X but is registered as 0x%02X in state %s", p, p.ID(), id, state)
+	}
+	return nil
+}
+
+// Packets lists every registered packet, ordered by direction, state and ID.
+func (r *PacketRegistry) Packets() []PacketInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var packets []PacketInfo
+	for state, byID := range r.serverBound {
+		for id, factory := range byID {
+			packets = append(packets, PacketInfo{ServerBound, state, id, reflect.TypeOf(factory())})
+		}
+	}
+	for state, byType := range r.clientBound {
+		for packetType, id := range byType {
+			packets = append(packets, PacketInfo{ClientBound, state, id, packetType})
+		}
+	}
+
+	sort.Slice(packets, func(i, j int) bool {
+		a, b := packets[i], packets[j]
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		if a.State != b.State {
+			return a.State < b.State
+		}
+		return a.ID < b.ID
+	})
+	return packets
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+	"Veloce/internal/protocol/packet/serverbound"
+	"Veloce/internal/registry"
+)
+
+func handleLoginAcknowledged(ctx *PacketContext, _ *serverbound.LoginAcknowledgedPacket) {
+	ctx.Connection.SetState(common.Configuration)
+	_ = ctx.Connection.SendPacket(&clientbound.ClientBoundKnownPacksPacket{
+		KnownPacks: ctx.Server.registries.KnownPacks(),
+	})
+}
+
+func handleKnownPacks(ctx *PacketContext, p *serverbound.ServerBoundKnownPacksPacket) {
+	registries := ctx.Server.registries
+
+	// Clients that share our packs already have the vanilla data, the rest get it inline
+	omitKnown := registries.SharesKnownPacks(p.KnownPacks)
+
+	packets, err := registries.Packets(omitKnown)
+	if err != nil {
+		ctx.Logger.Printf("Failed to build registry data: %v", err)
+		_ = ctx.Connection.Disconnect(component.Translatable("disconnect.genericReason", component.Text(err.Error())))
+		return
+	}
+
+	for _, packet := range packets {
+		if err := ctx.Connection.SendPacket(packet); err != nil {
+			ctx.Logger.Printf("Failed to send registry %s: %v", packet.RegistryID, err)
+			return
+		}
+	}
+
+	_ = ctx.Connection.SendPacket(&clientbound.FinishConfigurationPacket{})
+}
+
+func handleAcknowledgeFinishConfiguration(ctx *PacketContext, _ *serverbound.AcknowledgeFinishConfigurationPacket) {
+	s := ctx.Server
+	pc := ctx.Connection
+	pc.SetState(common.Play)
+
+	p := s.addPlayer(pc)
+
+	dimensionType := int32(0)
+	if dimensions, ok := s.registries.Get(registry.DimensionType); ok {
+		if id, ok := dimensions.NetworkID("minecraft:overworld"); ok {
+			dimensionType = id
+		}
+	}
+
+	packet := &clientbound.LoginPlayPacket{
+		EntityID:            p.GetEntityID(),
+		IsHardcore:          false,
+		DimensionNames:      nil,
+		MaxPlayers:          s.GetMaxPlayers(),
+		ViewDistance:        s.GetViewDistance(),
+		SimulationDistance:  s.GetSimulationDistance(),
+		ReducedDebugInfo:    false,
+		EnableRespawnScreen: false,
+		DoLimitedCrafting:   false,
+		DimensionType:       dimensionType,
+		DimensionName:       "minecraft:overworld",
+		HashedSeed:          7432018730923847123,
+		GameMode:            p.GetGameMode().ID(),
+		PreviousGameMode:    0,
+		IsDebug:             false,
+		IsFlat:              false,
+		HasDeathLocation:    false,
+		PortalCooldown:      0,
+		SeaLevel:            0,
+		EnforcesSecureChat:  false,
+	}
+	_ = pc.SendPacket(packet)
+
+	s.addToPlayerList(p)
+	s.callJoinEvent(p)
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"Veloce/internal/network/proxy"
+	"Veloce/internal/protocol/packet/clientbound"
+	"Veloce/internal/protocol/packet/serverbound"
+	"net"
+)
+
+// SendLoginPluginRequest sends a Login Plugin Request on channel. handler is called with
+// the client's answer; login does not continue until the request has been answered.
+func SendLoginPluginRequest(pc *common.PlayerConnection, channel string, data []byte, handler common.LoginQueryHandler) error {
+	id := pc.AddLoginQuery(handler)
+	return pc.SendPacket(&clientbound.LoginPluginRequestPacket{MessageID: id, Channel: channel, Data: data})
+}
+
+func handleLoginPluginResponse(ctx *PacketContext, p *serverbound.LoginPluginResponsePacket) {
+	handler, ok := ctx.Connection.TakeLoginQuery(p.MessageID)
+	if !ok {
+		ctx.Logger.Printf("Unexpected login plugin response %d", p.MessageID)
+		_ = ctx.Connection.Disconnect(component.Translatable("disconnect.packetError"))
+		return
+	}
+	handler(p.Data, p.Understood)
+}
+
+// SetVelocitySecret enables Velocity modern forwarding with the proxy's forwarding secret.
+// Players then have to connect through Velocity, which authenticates them and forwards
+// their address and profile. An empty secret disables forwarding.
+func (s *MinecraftServer) SetVelocitySecret(secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.velocitySecret = []byte(secret)
+}
+
+// IsVelocityEnabled reports whether Velocity modern forwarding is enabled.
+func (s *MinecraftServer) IsVelocityEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.velocitySecret) > 0
+}
+
+// SetBungeeCordForwarding enables BungeeCord legacy forwarding, which reads the player's
+// address, UUID and skin from the handshake. The server must not be reachable except
+// through the proxy, since the data is not signed.
+func (s *MinecraftServer) SetBungeeCordForwarding(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bungeeCordForwarding = enabled
+}
+
+// IsBungeeCordEnabled reports whether BungeeCord legacy forwarding is enabled.
+func (s *MinecraftServer) IsBungeeCordEnabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bungeeCordForwarding
+}
+
+// readBungeeCordForwarding applies the forwarding data of a login handshake. It reports
+// false if the handshake has none.
+func readBungeeCordForwarding(ctx *PacketContext, p *serverbound.HandshakePacket) bool {
+	host, forwarded, err := proxy.ParseBungeeCordAddress(p.ServerAddress)
+	if err != nil {
+		ctx.Logger.Printf("Rejected login: %v", err)
+		return false
+	}
+
+	ctx.Connection.SetHandshake(p.ProtocolVersion, host, p.ServerPort)
+	applyForwarding(ctx.Connection, forwarded)
+	return true
+}
+
+// requestVelocityForwarding asks Velocity for the player's info and completes login with it.
+func requestVelocityForwarding(ctx *PacketContext) error {
+	pc := ctx.Connection
+	return SendLoginPluginRequest(pc, proxy.VelocityChannel, proxy.VelocityRequest(), func(data []byte, understood bool) {
+		if !understood {
+			ctx.Logger.Printf("Rejected %s: not connected through Velocity", pc.GetGameProfile().Name)
+			_ = pc.Disconnect(component.Text("This server requires you to connect with Velocity."))
+			return
+		}
+
+		ctx.Server.mu.RLock()
+		secret := ctx.Server.velocitySecret
+		ctx.Server.mu.RUnlock()
+
+		forwarded, err := proxy.ReadVelocityResponse(data, secret)
+		if err != nil {
+			ctx.Logger.Printf("Rejected %s: %v", pc.GetGameProfile().Name, err)
+			_ = pc.Disconnect(component.Text("Unable to verify player details."))
+			return
+		}
+
+		applyForwarding(pc, forwarded)
+		if !throttleLogin(ctx) {
+			return
+		}
+		finishLogin(ctx, forwarded.Profile)
+	})
+}
+
+// applyForwarding replaces the connection's address and profile with those from the proxy.
+func applyForwarding(pc *common.PlayerConnection, forwarded proxy.ForwardedPlayer) {
+	port := 0
+	if addr, ok := pc.RemoteAddr().(*net.TCPAddr); ok {
+		port = addr.Port
+	}
+	pc.SetRemoteAddr(&net.TCPAddr{IP: forwarded.Address, Port: port})
+	pc.SetGameProfile(forwarded.Profile)
+}
+
+// EnableProxyProtocol expects connections from the given IP addresses and CIDR ranges to
+// start with a PROXY protocol v1 or v2 header, as sent by load balancers such as HAProxy.
+// Their client address is then taken from the header. With no addresses every connection
+// must send one. It must be called before Start.
+func (s *MinecraftServer) EnableProxyProtocol(trusted ...string) error {
+	proxies, err := proxy.ParseTrustedProxies(trusted...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proxyProtocol = true
+	s.trustedProxies = proxies
+	return nil
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+	"Veloce/internal/protocol/packet/serverbound"
+)
+
+// registerDefaultHandlers installs the built-in handlers. Packets that need no server
+// reaction, such as movement, have none.
+func (s *MinecraftServer) registerDefaultHandlers() {
+	HandlePacket(s, handleHandshake)
+	HandlePacket(s, handleStatusRequest)
+	HandlePacket(s, handlePingRequest)
+	HandlePacket(s, handleLoginStart)
+	HandlePacket(s, handleEncryptionResponse)
+	HandlePacket(s, handleLoginPluginResponse)
+	HandlePacket(s, handleLoginAcknowledged)
+	HandlePacket(s, handleKnownPacks)
+	HandlePacket(s, handleAcknowledgeFinishConfiguration)
+	HandlePacket(s, handleConfigurationKeepAlive)
+	HandlePacket(s, handlePlayKeepAlive)
+}
+
+func handleHandshake(ctx *PacketContext, p *serverbound.HandshakePacket) {
+	pc := ctx.Connection
+	pc.SetHandshake(p.ProtocolVersion, p.ServerAddress, p.ServerPort)
+
+	switch p.NextState {
+	case serverbound.IntentStatus:
+		// Any version may ask, the response tells it which one we run
+		pc.SetState(common.Status)
+	case serverbound.IntentLogin, serverbound.IntentTransfer:
+		pc.SetState(common.Login)
+		pc.SetTransferred(p.NextState == serverbound.IntentTransfer)
+
+		if reason, ok := checkLoginAllowed(ctx.Server, p); !ok {
+			ctx.Logger.Printf("Rejected login: protocol %d, intent %d", p.ProtocolVersion, p.NextState)
+			_ = pc.Disconnect(reason)
+			return
+		}
+		if ctx.Server.IsBungeeCordEnabled() && !readBungeeCordForwarding(ctx, p) {
+			_ = pc.Disconnect(component.Text("If you wish to use IP forwarding, please enable it in your BungeeCord config as well!"))
+			return
+		}
+		// Velocity only tells the player's address after Login Start, it is throttled then
+		if !ctx.Server.IsVelocityEnabled() && !throttleLogin(ctx) {
+			return
+		}
+	default:
+		ctx.Logger.Printf("Invalid handshake intent %d", p.NextState)
+		_ = pc.Close()
+	}
+}
+
+// throttleLogin disconnects the connection if its address logged in too recently. Behind a
+// forwarding proxy it must run after the player's address was applied, otherwise every
+// player would share the proxy's address. It reports whether the login may continue.
+func throttleLogin(ctx *PacketContext) bool {
+	pc := ctx.Connection
+	if ctx.Server.loginThrottle.allow(pc.RemoteAddr(), ctx.Server.GetLimits().LoginThrottle) {
+		return true
+	}
+	ctx.Server.metrics.LoginsThrottled.Add(1)
+	_ = pc.Disconnect(component.Text("Connection throttled! Please wait before reconnecting."))
+	return false
+}
+
+// checkLoginAllowed returns the disconnect reason for handshakes that may not log in.
+func checkLoginAllowed(s *MinecraftServer, p *serverbound.HandshakePacket) (component.Component, bool) {
+	version := component.Text(VersionName)
+	switch {
+	case p.ProtocolVersion < ProtocolVersion:
+		return component.Translatable("multiplayer.disconnect.outdated_client", version), false
+	case p.ProtocolVersion > ProtocolVersion:
+		return component.Translatable("multiplayer.disconnect.outdated_server", version), false
+	case p.NextState == serverbound.IntentTransfer && !s.AcceptsTransfers():
+		return component.Translatable("multiplayer.disconnect.transfers_disabled"), false
+	}
+	return component.Component{}, true
+}
+
+func handleStatusRequest(ctx *PacketContext, _ *serverbound.StatusRequestPacket) {
+	response, ok := ctx.Server.statusResponse(ctx.Connection)
+	if !ok {
+		_ = ctx.Connection.Close()
+		return
+	}
+	_ = ctx.Connection.SendPacket(&clientbound.StatusResponsePacket{Response: response})
+}
+
+func handlePingRequest(ctx *PacketContext, p *serverbound.PingRequestPacket) {
+	_ = ctx.Connection.SendPacket(&clientbound.PongPacket{Number: p.Number})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+	"Veloce/internal/protocol/packet/serverbound"
+	"time"
+)
+
+const (
+	KeepAliveInterval = 15 * time.Second // Time between Keep Alives once the last one was answered
+	KeepAliveTimeout  = 15 * time.Second // Time a client has to answer a Keep Alive
+
+	keepAliveCheckInterval = time.Second
+)
+
+// keepAlive sends due Keep Alives and disconnects clients that stopped answering.
+// It runs on the scheduler every keepAliveCheckInterval.
+func (s *MinecraftServer) keepAlive() {
+	for _, pc := range s.tcpServer.Connections() {
+		if state := pc.GetState(); state != common.Configuration && state != common.Play {
+			continue
+		}
+
+		if pc.KeepAliveExpired(KeepAliveTimeout) {
+			pc.Logger().Printf("%s timed out", pc.GetGameProfile().Name)
+			_ = pc.Disconnect(component.Translatable("disconnect.timeout"))
+			continue
+		}
+		if !pc.KeepAliveDue(KeepAliveInterval) {
+			continue
+		}
+
+		// Recorded before sending, since the answer can arrive before SendPacket returns
+		id := time.Now().UnixMilli()
+		pc.StartKeepAlive(id)
+		if err := sendKeepAlive(pc, id); err != nil {
+			pc.CancelKeepAlive(id)
+		}
+	}
+}
+
+// sendKeepAlive sends the Keep Alive packet of the state the connection is in when it is sent.
+// If the state changes in between, the packet validator rejects it and it is retried later.
+func sendKeepAlive(pc *common.PlayerConnection, id int64) error {
+	if pc.GetState() == common.Configuration {
+		return pc.SendPacket(&clientbound.ConfigurationKeepAlivePacket{KeepAliveID: id})
+	}
+	if err := pc.SendPacket(&clientbound.PlayKeepAlivePacket{KeepAliveID: id}); err != nil {
+		return err
+	}
+	// Waiting for the tick would count towards the latency
+	pc.Flush()
+	return nil
+}
+
+func handleConfigurationKeepAlive(ctx *PacketContext, p *serverbound.ConfigurationKeepAlivePacket) {
+	acknowledgeKeepAlive(ctx, p.KeepAliveID)
+}
+
+func handlePlayKeepAlive(ctx *PacketContext, p *serverbound.PlayKeepAlivePacket) {
+	if acknowledgeKeepAlive(ctx, p.KeepAliveID) && ctx.Player != nil {
+		ctx.Server.broadcastLatency(ctx.Player)
+	}
+}
+
+// acknowledgeKeepAlive disconnects clients echoing an ID that was never sent.
+func acknowledgeKeepAlive(ctx *PacketContext, id int64) bool {
+	if !ctx.Connection.AcknowledgeKeepAlive(id) {
+		ctx.Logger.Printf("Unexpected keep alive %d", id)
+		_ = ctx.Connection.Disconnect(component.Translatable("disconnect.timeout"))
+		return false
+	}
+	return true
+}
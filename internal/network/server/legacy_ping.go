@@ -0,0 +1,168 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Pre-Netty clients (before 1.7) ping with 0xFE instead of a handshake and expect the
+// answer as a 0xFF kick packet holding a UTF-16 string:
+//
+//	Beta 1.8 - 1.3: FE                       -> "motd§online§max"
+//	1.4 - 1.5:      FE 01                    -> "§1\0protocol\0version\0motd\0online\0max"
+//	1.6:            FE 01 FA "MC|PingHost"…  -> same as 1.4
+//
+// Like the vanilla server, the format is told apart by the bytes that arrived first.
+
+const (
+	legacyPingPacket = 0xFE
+	legacyKickPacket = 0xFF
+
+	// legacyPingProtocol is the protocol sent to legacy clients, marking the server incompatible
+	legacyPingProtocol = 127
+
+	legacyPingTimeout = 5 * time.Second
+)
+
+// LegacyPing is what a legacy ping told about the client. Only 1.6 clients send their
+// protocol and the address they connected to.
+type LegacyPing struct {
+	Beta     bool // Beta 1.8 to 1.3, which expect the short reply
+	Protocol int32
+	Host     string
+	Port     uint16
+}
+
+// prefixedConn replays bytes that were read while looking for a legacy ping.
+type prefixedConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *prefixedConn) Read(p []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(p, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
+
+// detectLegacyPing reads the first bytes of a new connection. It returns the legacy ping if
+// the client sent one, and the connection to use from now on.
+func detectLegacyPing(conn net.Conn) (net.Conn, *LegacyPing, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(ReadTimeout)); err != nil {
+		return nil, nil, err
+	}
+
+	first := make([]byte, 512)
+	n, err := conn.Read(first)
+	if err != nil {
+		return nil, nil, err
+	}
+	first = first[:n]
+
+	switch {
+	case first[0] != legacyPingPacket:
+	case len(first) == 1:
+		return conn, &LegacyPing{Beta: true}, nil
+	case first[1] == 0x01 && (len(first) == 2 || first[2] == 0xFA):
+		ping := &LegacyPing{}
+		if len(first) > 2 {
+			// The host is only informational, so a malformed one still gets an answer
+			_ = conn.SetReadDeadline(time.Now().Add(legacyPingTimeout))
+			_ = ping.readHost(io.MultiReader(bytes.NewReader(first[3:]), conn))
+		}
+		return conn, ping, nil
+	}
+	// A modern frame whose length happens to start with 0xFE
+	return &prefixedConn{Conn: conn, prefix: first}, nil, nil
+}
+
+// readHost reads the MC|PingHost plugin message 1.6 clients append to their ping.
+func (ping *LegacyPing) readHost(r io.Reader) error {
+	if _, err := readLegacyString(r); err != nil {
+		return err
+	}
+	var header struct {
+		Length   uint16
+		Protocol byte
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return err
+	}
+	host, err := readLegacyString(r)
+	if err != nil {
+		return err
+	}
+	var port int32
+	if err := binary.Read(r, binary.BigEndian, &port); err != nil {
+		return err
+	}
+
+	ping.Protocol = int32(header.Protocol)
+	ping.Host = host
+	ping.Port = uint16(port)
+	return nil
+}
+
+// readLegacyString reads a string prefixed with its length in UTF-16 code units.
+func readLegacyString(r io.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	units := make([]uint16, length)
+	if err := binary.Read(r, binary.BigEndian, units); err != nil {
+		return "", err
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// handleLegacyPing answers a legacy ping with the same data as the modern status response.
+func (s *MinecraftServer) handleLegacyPing(pc *common.PlayerConnection, ping *LegacyPing) {
+	if !ping.Beta {
+		pc.SetHandshake(ping.Protocol, ping.Host, ping.Port)
+	}
+
+	response, ok := s.statusResponse(pc)
+	if !ok {
+		return
+	}
+
+	online := strconv.Itoa(int(response.Players.Online))
+	max := strconv.Itoa(int(response.Players.Max))
+
+	var reply string
+	if ping.Beta {
+		// § separates the fields, so the MOTD cannot carry formatting
+		motd := strings.ReplaceAll(response.Description.PlainText(), string(component.SectionChar), "")
+		reply = strings.Join([]string{motd, online, max}, string(component.SectionChar))
+	} else {
+		reply = strings.Join([]string{
+			"§1",
+			strconv.Itoa(legacyPingProtocol),
+			response.Version.Name,
+			response.Description.Legacy(component.SectionChar),
+			online,
+			max,
+		}, "\x00")
+	}
+
+	units := utf16.Encode([]rune(reply))
+	buf := common.NewBuffer(nil)
+	buf.WriteByte(legacyKickPacket)
+	buf.WriteUint16(uint16(len(units)))
+	for _, unit := range units {
+		buf.WriteUint16(unit)
+	}
+	_ = pc.SendRaw(buf.Bytes())
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Limits bounds what clients may do before they are turned away. A zero field disables
+// that limit.
+type Limits struct {
+	// MaxConnections caps the number of open connections; further ones are closed at once.
+	MaxConnections int
+	// LoginThrottle is the minimum time between logins from one IP address, like the
+	// connection-throttle of Bukkit servers. Loopback addresses are exempt so local proxies work,
+	// and with BungeeCord or Velocity forwarding the forwarded player address is throttled.
+	LoginThrottle time.Duration
+	// MaxPacketsPerSecond and MaxBytesPerSecond disconnect clients that send more.
+	MaxPacketsPerSecond int
+	MaxBytesPerSecond   int
+}
+
+// DefaultLimits are generous enough for vanilla clients, which send at most a few hundred
+// packets per second even while flying through loaded chunks.
+var DefaultLimits = Limits{
+	MaxConnections:      1024,
+	LoginThrottle:       4 * time.Second,
+	MaxPacketsPerSecond: 500,
+	MaxBytesPerSecond:   1 << 20,
+}
+
+// rateMeter counts a connection's packets and bytes in one-second windows.
+type rateMeter struct {
+	windowStart time.Time
+	packets     int
+	bytes       int
+}
+
+// add records a packet of the given size and returns the totals of the current window.
+func (m *rateMeter) add(now time.Time, size int) (packets, bytes int) {
+	if now.Sub(m.windowStart) >= time.Second {
+		m.windowStart = now
+		m.packets, m.bytes = 0, 0
+	}
+	m.packets++
+	m.bytes += size
+	return m.packets, m.bytes
+}
+
+// loginThrottle remembers when each IP address last logged in.
+type loginThrottle struct {
+	mu         sync.Mutex
+	lastLogins map[string]time.Time
+}
+
+// allow records a login from addr and reports whether enough time passed since the last one.
+func (t *loginThrottle) allow(addr net.Addr, delay time.Duration) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if delay <= 0 || !ok || tcp.IP.IsLoopback() {
+		return true
+	}
+
+	now := time.Now()
+	ip := tcp.IP.String()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastLogins == nil {
+		t.lastLogins = make(map[string]time.Time)
+	}
+	last, seen := t.lastLogins[ip]
+	t.lastLogins[ip] = now
+
+	// Forget addresses whose throttle ran out so the map stays small
+	if len(t.lastLogins) > 1024 {
+		for other, at := range t.lastLogins {
+			if now.Sub(at) >= delay {
+				delete(t.lastLogins, other)
+			}
+		}
+	}
+	return !seen || now.Sub(last) >= delay
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/entity/player"
+	"Veloce/internal/network/auth"
+	"Veloce/internal/protocol/packet/clientbound"
+	"Veloce/internal/protocol/packet/serverbound"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sessionTimeout bounds how long a login waits for the session server.
+const sessionTimeout = 30 * time.Second
+
+func handleLoginStart(ctx *PacketContext, p *serverbound.LoginStartPacket) {
+	pc := ctx.Connection
+
+	if err := auth.ValidateUsername(p.Username); err != nil {
+		ctx.Logger.Printf("Rejected login for %q: %v", p.Username, err)
+		_ = pc.Disconnect(component.Translatable("multiplayer.disconnect.invalid_player_data"))
+		return
+	}
+
+	if ctx.Server.IsBungeeCordEnabled() {
+		// The handshake carried everything but the name, and the proxy authenticated the player
+		profile := pc.GetGameProfile()
+		profile.Name = p.Username
+		pc.SetGameProfile(profile)
+		finishLogin(ctx, profile)
+		return
+	}
+
+	if ctx.Server.IsVelocityEnabled() {
+		// The proxy authenticated the player and replaces this profile
+		pc.SetGameProfile(player.GameProfile{UUID: p.Uuid, Name: p.Username})
+		if err := requestVelocityForwarding(ctx); err != nil {
+			ctx.Logger.Printf("Failed to request forwarding for %s: %v", p.Username, err)
+			_ = pc.Close()
+		}
+		return
+	}
+
+	authenticator := ctx.Server.authenticator
+	if !authenticator.IsOnlineMode() {
+		profile := auth.OfflineProfile(p.Username)
+		pc.SetGameProfile(profile)
+		finishLogin(ctx, profile)
+		return
+	}
+
+	// Remember who is logging in until the client answers the encryption request
+	pc.SetGameProfile(player.GameProfile{UUID: p.Uuid, Name: p.Username})
+
+	if err := requestEncryption(ctx, authenticator); err != nil {
+		ctx.Logger.Printf("Failed to start encryption for %s: %v", p.Username, err)
+		_ = pc.Disconnect(component.Translatable("disconnect.loginFailedInfo", component.Text(err.Error())))
+	}
+}
+
+func requestEncryption(ctx *PacketContext, authenticator *auth.Authenticator) error {
+	keyPair, err := authenticator.KeyPair()
+	if err != nil {
+		return err
+	}
+	token, err := auth.NewVerifyToken()
+	if err != nil {
+		return err
+	}
+
+	ctx.Connection.SetVerifyToken(token)
+	return ctx.Connection.SendPacket(&clientbound.EncryptionRequestPacket{
+		ServerID:           "",
+		PublicKey:          keyPair.PublicKey(),
+		VerifyToken:        token,
+		ShouldAuthenticate: true,
+	})
+}
+
+func handleEncryptionResponse(ctx *PacketContext, p *serverbound.EncryptionResponsePacket) {
+	if err := authenticate(ctx, p); err != nil {
+		ctx.Logger.Printf("Login for %s failed: %v", ctx.Connection.GetGameProfile().Name, err)
+
+		reason := component.Translatable("disconnect.loginFailedInfo", component.Text(err.Error()))
+		if errors.Is(err, auth.ErrNotAuthenticated) {
+			reason = component.Translatable("multiplayer.disconnect.unverified_username")
+		}
+		_ = ctx.Connection.Disconnect(reason)
+	}
+}
+
+func authenticate(ctx *PacketContext, p *serverbound.EncryptionResponsePacket) error {
+	pc := ctx.Connection
+	authenticator := ctx.Server.authenticator
+
+	expected := pc.GetVerifyToken()
+	if expected == nil {
+		return fmt.Errorf("unexpected encryption response")
+	}
+	pc.SetVerifyToken(nil)
+
+	keyPair, err := authenticator.KeyPair()
+	if err != nil {
+		return err
+	}
+
+	token, err := keyPair.Decrypt(p.VerifyToken)
+	if err != nil || subtle.ConstantTimeCompare(token, expected) != 1 {
+		return fmt.Errorf("invalid verify token")
+	}
+	secret, err := keyPair.Decrypt(p.SharedSecret)
+	if err != nil || len(secret) != auth.SharedSecretLength {
+		return fmt.Errorf("invalid shared secret")
+	}
+
+	if err := pc.EnableEncryption(secret); err != nil {
+		return fmt.Errorf("enabling encryption: %w", err)
+	}
+
+	profile := pc.GetGameProfile()
+	serverHash := auth.ServerHash("", secret, keyPair.PublicKey())
+
+	timeout, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+
+	verified, err := authenticator.GetSessionVerifier().HasJoined(timeout, profile.Name, serverHash, "")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(verified.Name, profile.Name) {
+		return fmt.Errorf("session server returned profile %s", verified.Name)
+	}
+
+	pc.SetGameProfile(verified)
+	finishLogin(ctx, verified)
+	return nil
+}
+
+// finishLogin enables compression and completes login with the given profile.
+func finishLogin(ctx *PacketContext, profile player.GameProfile) {
+	pc := ctx.Connection
+	if threshold := pc.GetCompressionThreshold(); threshold >= 0 {
+		_ = pc.SendPacket(&clientbound.SetCompressionPacket{Threshold: int32(threshold)})
+		pc.EnableCompression()
+	}
+
+	_ = pc.SendPacket(&clientbound.LoginSuccessPacket{GameProfile: profile})
+}
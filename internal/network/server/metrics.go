@@ -0,0 +1,38 @@
+package server
+
+import "sync/atomic"
+
+// Metrics counts connections and the limits they hit. All fields are safe for concurrent use.
+type Metrics struct {
+	ConnectionsAccepted atomic.Int64
+	ConnectionsRejected atomic.Int64 // Closed because MaxConnections was reached
+	LoginsThrottled     atomic.Int64
+	PacketRateKicks     atomic.Int64
+	ByteRateKicks       atomic.Int64
+}
+
+// MetricsSnapshot is a copy of Metrics at one point in time.
+type MetricsSnapshot struct {
+	ConnectionsAccepted int64
+	ConnectionsRejected int64
+	LoginsThrottled     int64
+	PacketRateKicks     int64
+	ByteRateKicks       int64
+	OpenConnections     int64
+}
+
+// GetMetrics returns the server's current metrics.
+func (s *MinecraftServer) GetMetrics() MetricsSnapshot {
+	m := &s.metrics
+	snapshot := MetricsSnapshot{
+		ConnectionsAccepted: m.ConnectionsAccepted.Load(),
+		ConnectionsRejected: m.ConnectionsRejected.Load(),
+		LoginsThrottled:     m.LoginsThrottled.Load(),
+		PacketRateKicks:     m.PacketRateKicks.Load(),
+		ByteRateKicks:       m.ByteRateKicks.Load(),
+	}
+	if s.tcpServer != nil {
+		snapshot.OpenConnections = s.tcpServer.openConnections.Load()
+	}
+	return snapshot
+}
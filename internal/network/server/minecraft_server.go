@@ -1,14 +1,23 @@
 package server
 
 import (
+	"Veloce/internal/component"
 	"Veloce/internal/entity/player"
 	"Veloce/internal/event"
+	"Veloce/internal/event/events"
 	"Veloce/internal/network"
+	"Veloce/internal/network/auth"
+	"Veloce/internal/network/common"
+	"Veloce/internal/network/proxy"
 	"Veloce/internal/protocol"
+	"Veloce/internal/registry"
 	"Veloce/internal/scheduler"
 	"github.com/google/uuid"
 	"log"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -19,47 +28,199 @@ const (
 	DataPackVersion     = 71
 )
 
+const (
+	DefaultMaxPlayers         = 20
+	DefaultViewDistance       = 10
+	DefaultSimulationDistance = 10
+)
+
 type MinecraftServer struct {
-	tcpServer TCPServer
+	tcpServer *TCPServer
 	running   bool
 	mu        sync.RWMutex
 
-	playPlayers map[uuid.UUID]*player.Player
-	brandName   string
+	playPlayers          map[uuid.UUID]*player.Player
+	nextEntityID         atomic.Int32
+	brandName            string
+	compressionThreshold int
+	maxPlayers           int32
+	viewDistance         int32
+	simulationDistance   int32
+	motd                 component.Component
+	favicon              string
+	acceptsTransfers     bool
+	velocitySecret       []byte
+	bungeeCordForwarding bool
+	proxyProtocol        bool
+	trustedProxies       proxy.TrustedProxies
+	limits               Limits
+	loginThrottle        loginThrottle
+	metrics              Metrics
 
 	packetRegistry *network.PacketRegistry
+	packetHandlers map[reflect.Type]PacketHandler
+	handlersMu     sync.RWMutex
+	registries     *registry.Manager
+	authenticator  *auth.Authenticator
 	scheduler      *scheduler.Scheduler
 	ticker         *scheduler.Ticker
 	eventNode      *event.Node
 }
 
 func NewMinecraftServer() *MinecraftServer {
-	registry := network.NewPacketRegistry()
+	packetRegistry := network.NewPacketRegistry()
 	schedule := scheduler.NewScheduler()
 
+	registries, err := registry.NewVanillaManager()
+	if err != nil {
+		log.Fatalf("Failed to load vanilla registries: %v", err)
+	}
+
 	return &MinecraftServer{
-		running:        false,
-		playPlayers:    make(map[uuid.UUID]*player.Player),
-		packetRegistry: registry,
-		scheduler:      schedule,
-		ticker:         scheduler.NewTicker(schedule),
-		eventNode:      event.NewNode(),
+		running:              false,
+		playPlayers:          make(map[uuid.UUID]*player.Player),
+		compressionThreshold: common.DefaultCompressionThreshold,
+		maxPlayers:           DefaultMaxPlayers,
+		viewDistance:         DefaultViewDistance,
+		simulationDistance:   DefaultSimulationDistance,
+		motd:                 DefaultMOTD,
+		limits:               DefaultLimits,
+		packetRegistry:       packetRegistry,
+		packetHandlers:       make(map[reflect.Type]PacketHandler),
+		registries:           registries,
+		authenticator:        auth.NewAuthenticator(),
+		scheduler:            schedule,
+		ticker:               scheduler.NewTicker(schedule),
+		eventNode:            event.NewNode(),
 	}
 }
 
 func (s *MinecraftServer) Init() {
 	protocol.RegisterAllPackets(s.packetRegistry)
+	s.registerDefaultHandlers()
 }
 
 func (s *MinecraftServer) Start(address string) {
 	tcpServer := NewTCPServer(address, s.packetRegistry)
+	tcpServer.SetCompressionThreshold(s.GetCompressionThreshold())
+	s.mu.RLock()
+	tcpServer.SetProxyProtocol(s.proxyProtocol, s.trustedProxies)
+	tcpServer.SetLimits(s.limits)
+	s.mu.RUnlock()
+	tcpServer.SetMetrics(&s.metrics)
+	tcpServer.OnConnect(s.initConnection)
+	tcpServer.OnLegacyPing(s.handleLegacyPing)
+	tcpServer.OnPacket(s.handlePacket)
+	tcpServer.OnDisconnect(s.removePlayer)
+	s.tcpServer = tcpServer
+
+	// tcpServer.Start blocks until shutdown, so everything else must run first
+	s.running = true
+	s.ticker.Start()
+	s.scheduler.Schedule(s.keepAlive, scheduler.Async, keepAliveCheckInterval, keepAliveCheckInterval)
+	s.scheduler.Schedule(tcpServer.Flush, scheduler.TickEnd, scheduler.DefaultTickDuration, scheduler.DefaultTickDuration)
 
 	if err := tcpServer.Start(); err != nil {
 		log.Fatalf("Server exited with error: %v", err)
 	}
+}
 
-	s.running = true
-	s.ticker.Start()
+// addPlayer creates the Player for a connection that entered Play and makes it visible online.
+// A player already online with the same UUID is disconnected and has left the game before the
+// new one is added, since removePlayer no longer finds it once its connection closes.
+func (s *MinecraftServer) addPlayer(pc *common.PlayerConnection) *player.Player {
+	p := player.NewPlayer(s.nextEntityID.Add(1), pc.GetGameProfile(), pc)
+
+	s.mu.Lock()
+	previous, duplicate := s.playPlayers[p.GetUUID()]
+	delete(s.playPlayers, p.GetUUID())
+	s.mu.Unlock()
+
+	if duplicate {
+		_ = previous.Kick(component.Translatable("multiplayer.disconnect.duplicate_login"))
+		s.callQuitEvent(previous)
+	}
+
+	s.mu.Lock()
+	s.playPlayers[p.GetUUID()] = p
+	s.mu.Unlock()
+	return p
+}
+
+// callJoinEvent announces a player added by addPlayer once its client is in the world.
+func (s *MinecraftServer) callJoinEvent(p *player.Player) {
+	log.Printf("%s joined the game", p.GetUsername())
+	s.eventNode.CallEvent(&events.PlayerJoinEvent{Player: p})
+}
+
+// playerOf returns the online player using the given connection, or nil.
+func (s *MinecraftServer) playerOf(pc *common.PlayerConnection) *player.Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.playPlayers[pc.GetGameProfile().UUID]
+	if !ok || p.GetConnection() != pc {
+		return nil
+	}
+	return p
+}
+
+// removePlayer unregisters the player of a closed connection, if it had one.
+func (s *MinecraftServer) removePlayer(pc *common.PlayerConnection) {
+	id := pc.GetGameProfile().UUID
+
+	s.mu.Lock()
+	p, ok := s.playPlayers[id]
+	if ok && p.GetConnection() == pc {
+		delete(s.playPlayers, id)
+	} else {
+		ok = false
+	}
+	s.mu.Unlock()
+
+	if ok {
+		s.callQuitEvent(p)
+	}
+}
+
+// callQuitEvent removes a player that is no longer online from the player list and announces
+// that it left.
+func (s *MinecraftServer) callQuitEvent(p *player.Player) {
+	s.removeFromPlayerList(p)
+	log.Printf("%s left the game", p.GetUsername())
+	s.eventNode.CallEvent(&events.PlayerQuitEvent{Player: p})
+}
+
+// GetOnlinePlayers returns every player currently in the Play state.
+func (s *MinecraftServer) GetOnlinePlayers() []*player.Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	players := make([]*player.Player, 0, len(s.playPlayers))
+	for _, p := range s.playPlayers {
+		players = append(players, p)
+	}
+	return players
+}
+
+// GetPlayer returns the online player with the given UUID.
+func (s *MinecraftServer) GetPlayer(id uuid.UUID) (*player.Player, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.playPlayers[id]
+	return p, ok
+}
+
+// GetPlayerByName returns the online player with the given username, ignoring case.
+func (s *MinecraftServer) GetPlayerByName(name string) (*player.Player, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.playPlayers {
+		if strings.EqualFold(p.GetUsername(), name) {
+			return p, true
+		}
+	}
+	return nil, false
 }
 
 func (s *MinecraftServer) SetBrand(brand string) {
@@ -74,14 +235,134 @@ func (s *MinecraftServer) GetBrand() string {
 	return s.brandName
 }
 
+// GetRegistries returns the registries sent to clients during configuration.
+// Entries may be added or overridden until the first player joins.
+func (s *MinecraftServer) GetRegistries() *registry.Manager {
+	return s.registries
+}
+
+// SetOnlineMode selects between online mode, where logins are encrypted and verified with the
+// session server, and offline mode, where players get vanilla's name-derived offline UUIDs.
+func (s *MinecraftServer) SetOnlineMode(online bool) {
+	s.authenticator.SetOnlineMode(online)
+}
+
+func (s *MinecraftServer) IsOnlineMode() bool {
+	return s.authenticator.IsOnlineMode()
+}
+
+// SetSessionVerifier replaces the Mojang session server used in online mode, e.g. with a
+// local stand-in during tests.
+func (s *MinecraftServer) SetSessionVerifier(verifier auth.SessionVerifier) {
+	s.authenticator.SetSessionVerifier(verifier)
+}
+
+// SetCompressionThreshold sets the packet size from which packets are zlib-compressed.
+// common.CompressionDisabled turns compression off. It must be called before Start.
+func (s *MinecraftServer) SetCompressionThreshold(threshold int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.compressionThreshold = threshold
+}
+
+func (s *MinecraftServer) GetCompressionThreshold() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.compressionThreshold
+}
+
+func (s *MinecraftServer) SetMaxPlayers(maxPlayers int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxPlayers = maxPlayers
+}
+
+func (s *MinecraftServer) GetMaxPlayers() int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.maxPlayers
+}
+
+// SetViewDistance sets the chunk radius sent to players who join afterwards.
+func (s *MinecraftServer) SetViewDistance(distance int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.viewDistance = distance
+}
+
+func (s *MinecraftServer) GetViewDistance() int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.viewDistance
+}
+
+// SetSimulationDistance sets the chunk radius in which players who join afterwards see entities tick.
+func (s *MinecraftServer) SetSimulationDistance(distance int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simulationDistance = distance
+}
+
+func (s *MinecraftServer) GetSimulationDistance() int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.simulationDistance
+}
+
+// SetLimits sets the connection and rate limits. It must be called before Start.
+func (s *MinecraftServer) SetLimits(limits Limits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+}
+
+func (s *MinecraftServer) GetLimits() Limits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+// SetAcceptsTransfers sets whether clients sent here by another server's Transfer packet may log in.
+func (s *MinecraftServer) SetAcceptsTransfers(accepts bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.acceptsTransfers = accepts
+}
+
+func (s *MinecraftServer) AcceptsTransfers() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.acceptsTransfers
+}
+
+// GetAuthenticator returns the login security settings.
+func (s *MinecraftServer) GetAuthenticator() *auth.Authenticator {
+	return s.authenticator
+}
+
+// GetScheduler returns the server's task scheduler.
+func (s *MinecraftServer) GetScheduler() *scheduler.Scheduler {
+	return s.scheduler
+}
+
+// GetPacketRegistry returns the registry used to decode serverbound packets and to check
+// clientbound ones before they are sent.
+func (s *MinecraftServer) GetPacketRegistry() *network.PacketRegistry {
+	return s.packetRegistry
+}
+
 func (s *MinecraftServer) GetEventNode() *event.Node {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.eventNode
 }
 
+// Shutdown disconnects every client and stops the server.
 func (s *MinecraftServer) Shutdown() {
 	s.running = false
+	if s.tcpServer != nil {
+		_ = s.tcpServer.Shutdown(component.Translatable("multiplayer.disconnect.server_shutdown"))
+	}
 	s.ticker.Shutdown()
 	s.scheduler.Shutdown()
 }
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"Veloce/internal/entity/player"
+	"Veloce/internal/event/events"
+	"Veloce/internal/network/common"
+	"log"
+	"reflect"
+)
+
+// PacketContext carries everything a packet handler may need besides the packet itself.
+type PacketContext struct {
+	Server     *MinecraftServer
+	Connection *common.PlayerConnection
+	Player     *player.Player // nil until the connection has entered Play
+	Logger     *log.Logger
+}
+
+// PacketHandler handles a decoded serverbound packet.
+type PacketHandler func(ctx *PacketContext, packet common.ServerboundPacket)
+
+// HandlePacket sets the handler for packets of type T, replacing any built-in handler.
+//
+//	server.HandlePacket(srv, func(ctx *server.PacketContext, p *serverbound.ClientInformationPacket) {
+//		ctx.Logger.Printf("client information: %+v", p)
+//	})
+func HandlePacket[T common.ServerboundPacket](s *MinecraftServer, handler func(ctx *PacketContext, packet T)) {
+	s.SetPacketHandler(reflect.TypeFor[T](), func(ctx *PacketContext, packet common.ServerboundPacket) {
+		handler(ctx, packet.(T))
+	})
+}
+
+// SetPacketHandler sets the handler for packets of the given type. A nil handler removes it.
+func (s *MinecraftServer) SetPacketHandler(packetType reflect.Type, handler PacketHandler) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	if handler == nil {
+		delete(s.packetHandlers, packetType)
+		return
+	}
+	s.packetHandlers[packetType] = handler
+}
+
+// GetPacketHandler returns the handler for packets of the given type.
+func (s *MinecraftServer) GetPacketHandler(packetType reflect.Type) (PacketHandler, bool) {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+	handler, ok := s.packetHandlers[packetType]
+	return handler, ok
+}
+
+// initConnection routes the connection's outgoing packets through PacketOutgoingEvent.
+func (s *MinecraftServer) initConnection(pc *common.PlayerConnection) {
+	pc.SetSendInterceptor(s.callOutgoingEvent)
+}
+
+func (s *MinecraftServer) callOutgoingEvent(pc *common.PlayerConnection, packet common.ClientboundPacket) (common.ClientboundPacket, bool) {
+	evt := &events.PacketOutgoingEvent{Packet: packet, Connection: pc}
+	s.eventNode.CallEvent(evt)
+	return evt.Packet, !evt.IsCancelled()
+}
+
+// handlePacket calls PacketIncomingEvent and dispatches the resulting packet to its handler.
+// Packets without a handler are ignored.
+func (s *MinecraftServer) handlePacket(pc *common.PlayerConnection, packet common.ServerboundPacket) {
+	evt := &events.PacketIncomingEvent{Packet: packet, Connection: pc}
+	s.eventNode.CallEvent(evt)
+	if evt.IsCancelled() || evt.Packet == nil {
+		return
+	}
+	packet = evt.Packet
+
+	handler, ok := s.GetPacketHandler(reflect.TypeOf(packet))
+	if !ok {
+		return
+	}
+
+	ctx := &PacketContext{
+		Server:     s,
+		Connection: pc,
+		Logger:     pc.Logger(),
+	}
+	if pc.GetState() == common.Play {
+		ctx.Player = s.playerOf(pc)
+	}
+	handler(ctx, packet)
+}
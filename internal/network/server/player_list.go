@@ -0,0 +1,63 @@
+package server
+
+import (
+	"Veloce/internal/entity/player"
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+	"github.com/google/uuid"
+)
+
+const playerListAddActions = clientbound.PlayerInfoAddPlayer |
+	clientbound.PlayerInfoUpdateGameMode |
+	clientbound.PlayerInfoUpdateListed |
+	clientbound.PlayerInfoUpdateLatency
+
+func playerInfoEntry(p *player.Player) clientbound.PlayerInfoEntry {
+	return clientbound.PlayerInfoEntry{
+		Profile:  p.GetGameProfile(),
+		GameMode: int32(p.GetGameMode().ID()),
+		Listed:   true,
+		Latency:  int32(p.GetLatency().Milliseconds()),
+	}
+}
+
+// addToPlayerList shows a joining player in everyone's tab list and sends it the current list.
+func (s *MinecraftServer) addToPlayerList(joined *player.Player) {
+	players := s.GetOnlinePlayers()
+
+	entries := make([]clientbound.PlayerInfoEntry, 0, len(players))
+	for _, p := range players {
+		entries = append(entries, playerInfoEntry(p))
+	}
+	_ = joined.GetConnection().SendPacket(&clientbound.PlayerInfoUpdatePacket{
+		Actions: playerListAddActions,
+		Entries: entries,
+	})
+
+	s.broadcast(joined, &clientbound.PlayerInfoUpdatePacket{
+		Actions: playerListAddActions,
+		Entries: []clientbound.PlayerInfoEntry{playerInfoEntry(joined)},
+	})
+}
+
+// removeFromPlayerList removes a player that left from everyone's tab list.
+func (s *MinecraftServer) removeFromPlayerList(left *player.Player) {
+	s.broadcast(left, &clientbound.PlayerInfoRemovePacket{UUIDs: []uuid.UUID{left.GetUUID()}})
+}
+
+// broadcastLatency updates the ping shown for p in everyone's tab list.
+func (s *MinecraftServer) broadcastLatency(p *player.Player) {
+	s.broadcast(nil, &clientbound.PlayerInfoUpdatePacket{
+		Actions: clientbound.PlayerInfoUpdateLatency,
+		Entries: []clientbound.PlayerInfoEntry{playerInfoEntry(p)},
+	})
+}
+
+// broadcast sends a packet to every online player except the given one.
+func (s *MinecraftServer) broadcast(except *player.Player, packet common.ClientboundPacket) {
+	for _, p := range s.GetOnlinePlayers() {
+		if p != except {
+			_ = p.GetConnection().SendPacket(packet)
+		}
+	}
+}
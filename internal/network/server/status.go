@@ -0,0 +1,103 @@
+package server
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/event/events"
+	"Veloce/internal/network/common"
+	"Veloce/internal/protocol/packet/clientbound"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"os"
+)
+
+const (
+	FaviconSize     = 64 // Width and height the client requires of a favicon
+	MaxStatusSample = 12 // Players listed when hovering over the player count, as in vanilla
+)
+
+// ErrInvalidFavicon is returned for favicons that are not 64x64 PNG images.
+var ErrInvalidFavicon = errors.New("favicon must be a 64x64 PNG image")
+
+// DefaultMOTD is the server list description until SetMOTD is called.
+var DefaultMOTD = component.Text("A Veloce Server")
+
+// SetMOTD sets the description shown in the server list.
+func (s *MinecraftServer) SetMOTD(motd component.Component) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.motd = motd
+}
+
+func (s *MinecraftServer) GetMOTD() component.Component {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.motd
+}
+
+// SetFavicon sets the server list icon from PNG data. nil removes it.
+func (s *MinecraftServer) SetFavicon(data []byte) error {
+	favicon := ""
+	if data != nil {
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidFavicon, err)
+		}
+		if config.Width != FaviconSize || config.Height != FaviconSize {
+			return fmt.Errorf("%w: image is %dx%d", ErrInvalidFavicon, config.Width, config.Height)
+		}
+		favicon = "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.favicon = favicon
+	return nil
+}
+
+// LoadFavicon sets the server list icon from a PNG file.
+func (s *MinecraftServer) LoadFavicon(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return s.SetFavicon(data)
+}
+
+// GetFavicon returns the server list icon as a data URI, or an empty string if none is set.
+func (s *MinecraftServer) GetFavicon() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.favicon
+}
+
+// statusResponse builds the server list entry for a connection and lets ServerListPingEvent
+// listeners change it. It reports false if a listener cancelled the ping.
+func (s *MinecraftServer) statusResponse(pc *common.PlayerConnection) (clientbound.StatusResponse, bool) {
+	players := s.GetOnlinePlayers()
+
+	sample := make([]clientbound.StatusSample, 0, min(len(players), MaxStatusSample))
+	for _, p := range players[:min(len(players), MaxStatusSample)] {
+		sample = append(sample, clientbound.StatusSample{Name: p.GetUsername(), ID: p.GetUUID()})
+	}
+
+	response := &clientbound.StatusResponse{
+		Version: clientbound.StatusVersion{Name: VersionName, Protocol: ProtocolVersion},
+		Players: clientbound.StatusPlayers{
+			Max:    s.GetMaxPlayers(),
+			Online: int32(len(players)),
+			Sample: sample,
+		},
+		Description: s.GetMOTD(),
+		Favicon:     s.GetFavicon(),
+	}
+
+	evt := &events.ServerListPingEvent{Connection: pc, Response: response}
+	s.eventNode.CallEvent(evt)
+	if evt.IsCancelled() || evt.Response == nil {
+		return clientbound.StatusResponse{}, false
+	}
+	return *evt.Response, true
+}
@@ -1,36 +1,100 @@
 package server
 
 import (
+	"Veloce/internal/component"
 	"Veloce/internal/network"
 	"Veloce/internal/network/common"
+	"Veloce/internal/network/proxy"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	MaxPacketLength = 2097151 // Maximum allowed packet length (2^21 - 1)
+	MaxPacketLength = 2097151          // Maximum allowed packet length (2^21 - 1)
+	ReadTimeout     = 30 * time.Second // Idle time allowed before Configuration
 )
 
 // TCPServer represents a simplified TCP server
 type TCPServer struct {
-	listener       net.Listener
-	addr           string
-	running        bool
-	connections    sync.Map
-	packetRegistry *network.PacketRegistry
+	listener             net.Listener
+	addr                 string
+	running              bool
+	connections          sync.Map
+	packetRegistry       *network.PacketRegistry
+	compressionThreshold int
+	proxyProtocol        bool
+	trustedProxies       proxy.TrustedProxies
+	limits               Limits
+	metrics              *Metrics
+	openConnections      atomic.Int64
+
+	onConnect    func(pc *common.PlayerConnection)
+	onLegacyPing func(pc *common.PlayerConnection, ping *LegacyPing)
+	onPacket     func(pc *common.PlayerConnection, packet common.ServerboundPacket)
+	onDisconnect func(pc *common.PlayerConnection)
 }
 
 // NewTCPServer creates a new simplified TCP server
 func NewTCPServer(addr string, packetRegistry *network.PacketRegistry) *TCPServer {
 	return &TCPServer{
-		addr:           addr,
-		packetRegistry: packetRegistry,
+		addr:                 addr,
+		packetRegistry:       packetRegistry,
+		compressionThreshold: common.DefaultCompressionThreshold,
+		limits:               DefaultLimits,
+		metrics:              &Metrics{},
 	}
 }
 
+// SetLimits sets the connection and rate limits. It must be called before Start.
+func (s *TCPServer) SetLimits(limits Limits) {
+	s.limits = limits
+}
+
+// SetMetrics sets where connection metrics are counted.
+func (s *TCPServer) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+}
+
+// SetCompressionThreshold sets the threshold new connections negotiate during login.
+// common.CompressionDisabled turns compression off.
+func (s *TCPServer) SetCompressionThreshold(threshold int) {
+	s.compressionThreshold = threshold
+}
+
+// SetProxyProtocol makes connections from trusted addresses start with a PROXY protocol
+// header, whose client address replaces theirs. Other connections are taken as they are.
+func (s *TCPServer) SetProxyProtocol(enabled bool, trusted proxy.TrustedProxies) {
+	s.proxyProtocol = enabled
+	s.trustedProxies = trusted
+}
+
+// OnConnect sets the function called for each new connection before its first packet is read.
+func (s *TCPServer) OnConnect(fn func(pc *common.PlayerConnection)) {
+	s.onConnect = fn
+}
+
+// OnLegacyPing sets the function that answers pings from clients older than 1.7.
+func (s *TCPServer) OnLegacyPing(fn func(pc *common.PlayerConnection, ping *LegacyPing)) {
+	s.onLegacyPing = fn
+}
+
+// OnPacket sets the function that handles every decoded serverbound packet.
+func (s *TCPServer) OnPacket(fn func(pc *common.PlayerConnection, packet common.ServerboundPacket)) {
+	s.onPacket = fn
+}
+
+// OnDisconnect sets the function called once a connection has been closed.
+func (s *TCPServer) OnDisconnect(fn func(pc *common.PlayerConnection)) {
+	s.onDisconnect = fn
+}
+
 func (s *TCPServer) Start() error {
 	listener, err := net.Listen("tcp", s.addr)
 	if err != nil {
@@ -50,31 +114,70 @@ func (s *TCPServer) Start() error {
 			continue
 		}
 
+		if limit := s.limits.MaxConnections; limit > 0 && s.openConnections.Load() >= int64(limit) {
+			s.metrics.ConnectionsRejected.Add(1)
+			conn.Close()
+			continue
+		}
+		s.metrics.ConnectionsAccepted.Add(1)
+		s.openConnections.Add(1)
+
 		go s.handleConnection(conn)
 	}
 	return nil
 }
 
-func (s *TCPServer) Shutdown() error {
+// Shutdown stops accepting connections and disconnects every client with reason.
+func (s *TCPServer) Shutdown(reason common.Text) error {
 	s.running = false
 
 	if s.listener != nil {
 		s.listener.Close()
 	}
 
-	s.connections.Range(func(key, value interface{}) bool {
-		if pc, ok := value.(*common.PlayerConnection); ok {
-			pc.Close()
-		}
-		return true
-	})
+	connections := s.Connections()
+	for _, pc := range connections {
+		_ = pc.Disconnect(reason)
+	}
+	// Give the writers the chance to deliver the reason before the process exits
+	for _, pc := range connections {
+		<-pc.Done()
+	}
 
 	return nil
 }
 
-func (s *TCPServer) readPacket(conn net.Conn) (*common.Buffer, error) {
-	if err := conn.SetReadDeadline(time.Now().Add(30 * time.Second)); err != nil {
-		return nil, fmt.Errorf("failed to set read deadline: %w", err)
+// Flush sends the packets queued on every connection.
+func (s *TCPServer) Flush() {
+	for _, pc := range s.Connections() {
+		pc.Flush()
+	}
+}
+
+// Connections returns every open connection.
+func (s *TCPServer) Connections() []*common.PlayerConnection {
+	var connections []*common.PlayerConnection
+	s.connections.Range(func(_, value any) bool {
+		connections = append(connections, value.(*common.PlayerConnection))
+		return true
+	})
+	return connections
+}
+
+// readPacket reads the next packet and returns it along with its size on the wire.
+func (s *TCPServer) readPacket(pc *common.PlayerConnection) (*common.Buffer, int, error) {
+	conn := pc.Conn()
+	if conn == nil {
+		return nil, 0, io.EOF
+	}
+
+	// Once keep-alives run they detect dead clients, so only earlier states time out on reads
+	deadline := time.Time{}
+	if pc.GetState() < common.Configuration {
+		deadline = time.Now().Add(ReadTimeout)
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, 0, fmt.Errorf("failed to set read deadline: %w", err)
 	}
 
 	// Create a temporary buffer to read the VarInt length
@@ -84,7 +187,7 @@ func (s *TCPServer) readPacket(conn net.Conn) (*common.Buffer, error) {
 	// Read VarInt byte by byte
 	for i := 0; i < 5; i++ {
 		if _, err := conn.Read(tempBuf[bytesRead : bytesRead+1]); err != nil {
-			return nil, fmt.Errorf("reading VarInt: %w", err)
+			return nil, 0, fmt.Errorf("reading VarInt: %w", err)
 		}
 		bytesRead++
 
@@ -98,50 +201,174 @@ func (s *TCPServer) readPacket(conn net.Conn) (*common.Buffer, error) {
 	varintBuf := common.NewBuffer(tempBuf[:bytesRead])
 	length, err := varintBuf.ReadVarInt()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read packet length: %w", err)
+		return nil, 0, fmt.Errorf("failed to read packet length: %w", err)
 	}
 
 	if length <= 0 || length > MaxPacketLength {
-		return nil, fmt.Errorf("invalid packet length: %d", length)
+		return nil, 0, fmt.Errorf("invalid packet length: %d", length)
 	}
 
 	// Read the actual packet data
 	packetData := make([]byte, length)
 	if _, err := io.ReadFull(conn, packetData); err != nil {
-		return nil, fmt.Errorf("reading packet data: %w", err)
+		return nil, 0, fmt.Errorf("reading packet data: %w", err)
 	}
 
-	// Return a buffer containing the packet data
-	return common.NewBuffer(packetData), nil
+	// Return a buffer containing the (decompressed) packet data
+	packetBuf, err := pc.DecodeFrame(packetData)
+	return packetBuf, bytesRead + int(length), err
 }
 
 func (s *TCPServer) handleConnection(conn net.Conn) {
-	defer conn.Close()
+	defer s.openConnections.Add(-1)
+
+	if s.proxyProtocol && s.trustedProxies.Contains(conn.RemoteAddr()) {
+		if err := conn.SetReadDeadline(time.Now().Add(ReadTimeout)); err != nil {
+			conn.Close()
+			return
+		}
+		proxied, err := proxy.AcceptProxyHeader(conn)
+		if err != nil {
+			fmt.Printf("Rejected connection from %s: %v\n", conn.RemoteAddr(), err)
+			conn.Close()
+			return
+		}
+		conn = proxied
+	}
+
+	prefixed, ping, err := detectLegacyPing(conn)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn = prefixed
+
+	// From here on the connection's writer goroutine closes conn once pc is closed
 	pc := common.NewPlayerConnection(conn)
+	if ping != nil {
+		if s.onLegacyPing != nil {
+			s.onLegacyPing(pc, ping)
+		}
+		pc.Close()
+		return
+	}
+
+	pc.SetCompressionThreshold(s.compressionThreshold)
+	pc.SetPacketValidator(s.packetRegistry)
+	if s.onConnect != nil {
+		s.onConnect(pc)
+	}
 	connID := conn.RemoteAddr().String()
 	s.connections.Store(connID, pc)
 	defer s.connections.Delete(connID)
+	defer func() {
+		pc.Close()
+		if s.onDisconnect != nil {
+			s.onDisconnect(pc)
+		}
+	}()
 
+	var meter rateMeter
+	ignored := make(map[int32]bool) // Unknown Play packet IDs already logged
 	for s.running {
-		packetBuf, err := s.readPacket(conn)
+		packetBuf, size, err := s.readPacket(pc)
 		if err != nil {
-			if err == io.EOF {
-				fmt.Printf("Client %s disconnected\n", conn.RemoteAddr())
-				return // Exit the handler - don't continue the loop
+			switch {
+			case errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed):
+				pc.Logger().Printf("Client disconnected")
+			case errors.Is(err, os.ErrDeadlineExceeded):
+				pc.Logger().Printf("Client timed out")
+				_ = pc.Disconnect(component.Translatable("disconnect.timeout"))
+			default:
+				pc.Logger().Printf("Error reading packet: %v", err)
+				_ = pc.Disconnect(packetErrorReason(err))
 			}
-			fmt.Printf("Error reading packet from %s: %v\n", conn.RemoteAddr(), err)
-			return // Exit on any error - don't continue
+			return
 		}
 
-		currentState := pc.GetState()
-		packetId, err := packetBuf.ReadVarInt()
-		if err != nil {
-			fmt.Printf("failed to read packet ID: %s", err)
-			continue
+		packets, bytes := meter.add(time.Now(), size)
+		if limit := s.limits.MaxPacketsPerSecond; limit > 0 && packets > limit {
+			s.metrics.PacketRateKicks.Add(1)
+			pc.Logger().Printf("Sent more than %d packets per second", limit)
+			_ = pc.Disconnect(component.Translatable("disconnect.exceeded_packet_rate"))
+			return
+		}
+		if limit := s.limits.MaxBytesPerSecond; limit > 0 && bytes > limit {
+			s.metrics.ByteRateKicks.Add(1)
+			pc.Logger().Printf("Sent more than %d bytes per second", limit)
+			_ = pc.Disconnect(component.Translatable("disconnect.exceeded_packet_rate"))
+			return
+		}
+
+		if err := s.handlePacket(pc, packetBuf); err != nil {
+			// Play has many packets we do not handle yet, vanilla clients send them all the time
+			var unknown unknownPacketError
+			if errors.As(err, &unknown) && unknown.state == common.Play {
+				if !ignored[unknown.id] {
+					ignored[unknown.id] = true
+					pc.Logger().Printf("Ignoring %v", err)
+				}
+				continue
+			}
+			pc.Logger().Printf("Bad packet: %v", err)
+			_ = pc.Disconnect(packetErrorReason(err))
+			return
+		}
+	}
+}
+
+// handlePacket decodes a packet and passes it to onPacket. A panic on the way is returned as
+// an error, so a single client cannot take the server down.
+func (s *TCPServer) handlePacket(pc *common.PlayerConnection, buf *common.Buffer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pc.Logger().Printf("Panic while handling packet: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("%v", r)
 		}
+	}()
+
+	packet, err := s.decodePacket(pc.GetState(), buf)
+	if err != nil {
+		return err
+	}
+	if s.onPacket != nil {
+		s.onPacket(pc, packet)
+	}
+	return nil
+}
 
-		packet, _ := s.packetRegistry.GetServerBoundPacket(currentState, packetId)
-		packet.Read(packetBuf)
-		packet.Handle(pc)
+// decodePacket reads the packet ID and payload. The payload must be consumed exactly.
+func (s *TCPServer) decodePacket(state common.ConnectionState, buf *common.Buffer) (common.ServerboundPacket, error) {
+	id, err := buf.ReadVarInt()
+	if err != nil {
+		return nil, fmt.Errorf("reading packet ID: %w", err)
 	}
+
+	packet, ok := s.packetRegistry.GetServerBoundPacket(state, id)
+	if !ok {
+		return nil, unknownPacketError{id: id, state: state}
+	}
+	if err := packet.Read(buf); err != nil {
+		return nil, fmt.Errorf("decoding packet 0x%02X in state %s: %w", id, state, err)
+	}
+	if buf.Len() > 0 {
+		return nil, fmt.Errorf("packet 0x%02X in state %s has %d bytes left over", id, state, buf.Len())
+	}
+	return packet, nil
+}
+
+// unknownPacketError is returned by decodePacket for IDs no packet is registered for.
+type unknownPacketError struct {
+	id    int32
+	state common.ConnectionState
+}
+
+func (e unknownPacketError) Error() string {
+	return fmt.Sprintf("unknown packet 0x%02X in state %s", e.id, e.state)
+}
+
+// packetErrorReason tells the client what was wrong with its packet, the way vanilla reports
+// decoder exceptions.
+func packetErrorReason(err error) component.Component {
+	return component.Translatable("disconnect.genericReason", component.Text(err.Error()))
 }
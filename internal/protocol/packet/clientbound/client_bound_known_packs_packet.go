@@ -1,20 +1,17 @@
 package clientbound
 
-import (
-	"Veloce/internal/network/common"
-)
-
-type ClientBoundKnownPacksPacket struct {
-	//TODO
+// KnownPack identifies a data pack both sides may already have, letting registry data be omitted.
+//
+//packet:type
+type KnownPack struct {
+	Namespace string
+	ID        string
+	Version   string
 }
 
-func (p *ClientBoundKnownPacksPacket) ID() int32 {
-	return 0x0E
-}
-
-func (p *ClientBoundKnownPacksPacket) Write(buf *common.Buffer) {
-	buf.WriteVarInt(1)
-	buf.WriteString("minecraft")
-	buf.WriteString("core")
-	buf.WriteString("1.21.5")
+// ClientBoundKnownPacksPacket tells the client which data packs the server knows about
+//
+//packet:configuration 0x0E
+type ClientBoundKnownPacksPacket struct {
+	KnownPacks []KnownPack
 }
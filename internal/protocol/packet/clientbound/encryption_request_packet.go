@@ -0,0 +1,11 @@
+package clientbound
+
+// EncryptionRequestPacket starts the key exchange during login
+//
+//packet:login 0x01
+type EncryptionRequestPacket struct {
+	ServerID           string
+	PublicKey          []byte
+	VerifyToken        []byte
+	ShouldAuthenticate bool
+}
@@ -0,0 +1,5 @@
+// Package clientbound holds the packets the server sends. Packets with a //packet: directive
+// have their ID and Write methods generated by packetgen; the rest are written by hand.
+package clientbound
+
+//go:generate go run Veloce/cmd/packetgen
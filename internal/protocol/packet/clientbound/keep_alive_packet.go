@@ -0,0 +1,15 @@
+package clientbound
+
+// ConfigurationKeepAlivePacket asks the client to echo KeepAliveID during Configuration.
+//
+//packet:configuration 0x04
+type ConfigurationKeepAlivePacket struct {
+	KeepAliveID int64
+}
+
+// PlayKeepAlivePacket asks the client to echo KeepAliveID during Play.
+//
+//packet:play 0x26
+type PlayKeepAlivePacket struct {
+	KeepAliveID int64
+}
@@ -0,0 +1,10 @@
+package clientbound
+
+// LoginPluginRequestPacket asks the client, or a proxy in front of it, for custom data during login.
+//
+//packet:login 0x04
+type LoginPluginRequestPacket struct {
+	MessageID int32
+	Channel   string
+	Data      []byte `mc:"rest"`
+}
@@ -0,0 +1,87 @@
+package clientbound
+
+import (
+	"Veloce/internal/component"
+	"Veloce/internal/network/common"
+	"github.com/google/uuid"
+)
+
+// PlayerInfoAction selects which fields of each entry a PlayerInfoUpdatePacket carries.
+type PlayerInfoAction byte
+
+const (
+	PlayerInfoAddPlayer PlayerInfoAction = 1 << iota
+	PlayerInfoInitializeChat
+	PlayerInfoUpdateGameMode
+	PlayerInfoUpdateListed
+	PlayerInfoUpdateLatency
+	PlayerInfoUpdateDisplayName
+	PlayerInfoUpdateListPriority
+	PlayerInfoUpdateHat
+)
+
+// PlayerInfoEntry is one player in the tab list. Only the fields of the packet's actions are sent;
+// chat sessions are always sent as absent.
+type PlayerInfoEntry struct {
+	Profile      common.GameProfile
+	GameMode     int32
+	Listed       bool
+	Latency      int32                // milliseconds
+	DisplayName  *component.Component // nil shows the player's name
+	ListPriority int32
+	ShowHat      bool
+}
+
+// PlayerInfoUpdatePacket adds players to the client's player list or updates their entries.
+type PlayerInfoUpdatePacket struct {
+	Actions PlayerInfoAction
+	Entries []PlayerInfoEntry
+}
+
+func (p *PlayerInfoUpdatePacket) ID() int32 {
+	return 0x3F
+}
+
+func (p *PlayerInfoUpdatePacket) Write(buf *common.Buffer) {
+	buf.WriteByte(byte(p.Actions))
+	buf.WriteVarInt(int32(len(p.Entries)))
+
+	for _, entry := range p.Entries {
+		if p.Actions&PlayerInfoAddPlayer != 0 {
+			buf.WriteGameProfile(entry.Profile)
+		} else {
+			buf.WriteUUID(entry.Profile.UUID)
+		}
+		if p.Actions&PlayerInfoInitializeChat != 0 {
+			buf.WriteBool(false)
+		}
+		if p.Actions&PlayerInfoUpdateGameMode != 0 {
+			buf.WriteVarInt(entry.GameMode)
+		}
+		if p.Actions&PlayerInfoUpdateListed != 0 {
+			buf.WriteBool(entry.Listed)
+		}
+		if p.Actions&PlayerInfoUpdateLatency != 0 {
+			buf.WriteVarInt(entry.Latency)
+		}
+		if p.Actions&PlayerInfoUpdateDisplayName != 0 {
+			buf.WriteBool(entry.DisplayName != nil)
+			if entry.DisplayName != nil {
+				buf.WriteNBT(entry.DisplayName.NBT())
+			}
+		}
+		if p.Actions&PlayerInfoUpdateListPriority != 0 {
+			buf.WriteVarInt(entry.ListPriority)
+		}
+		if p.Actions&PlayerInfoUpdateHat != 0 {
+			buf.WriteBool(entry.ShowHat)
+		}
+	}
+}
+
+// PlayerInfoRemovePacket removes players from the client's player list.
+//
+//packet:play 0x3E
+type PlayerInfoRemovePacket struct {
+	UUIDs []uuid.UUID
+}
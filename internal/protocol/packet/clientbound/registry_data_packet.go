@@ -4,8 +4,17 @@ import (
 	"Veloce/internal/network/common"
 )
 
+// RegistryEntry is a single entry of a synchronised registry.
+// A nil Data tells the client to take the entry from a known pack.
+type RegistryEntry struct {
+	ID   string // Identifier
+	Data common.Tag
+}
+
+// RegistryDataPacket sends the contents of one registry during configuration
 type RegistryDataPacket struct {
-	data []byte
+	RegistryID string // Identifier
+	Entries    []RegistryEntry
 }
 
 func (p *RegistryDataPacket) ID() int32 {
@@ -13,7 +22,13 @@ func (p *RegistryDataPacket) ID() int32 {
 }
 
 func (p *RegistryDataPacket) Write(buf *common.Buffer) {
-	for i := range p.data {
-		buf.WriteByte(p.data[i])
+	buf.WriteString(p.RegistryID)
+	buf.WriteVarInt(int32(len(p.Entries)))
+	for _, entry := range p.Entries {
+		buf.WriteString(entry.ID)
+		buf.WriteBool(entry.Data != nil)
+		if entry.Data != nil {
+			buf.WriteNBT(entry.Data)
+		}
 	}
 }
@@ -0,0 +1,8 @@
+package clientbound
+
+// SetCompressionPacket enables compression for all following packets
+//
+//packet:login 0x03
+type SetCompressionPacket struct {
+	Threshold int32
+}
@@ -1,33 +1,44 @@
 package clientbound
 
 import (
+	"Veloce/internal/component"
 	"Veloce/internal/network/common"
+	"encoding/json"
+	"github.com/google/uuid"
 )
 
-const JsonExample = `{
-    "version": {
-        "name": "1.21.5",
-        "protocol": 770
-    },
-    "players": {
-        "max": 100,
-        "online": 5,
-        "sample": [
-            {
-                "name": "thinkofdeath",
-                "id": "4566e69f-c907-48ee-8d71-d7ba5aa00d20"
-            }
-        ]
-    },
-    "description": {
-        "text": "Hello, world!"
-    },
-    "favicon": "data:image/png;base64,<data>",
-    "enforcesSecureChat": false
-}`
+// StatusResponse is the server list entry sent in reply to a status request.
+type StatusResponse struct {
+	Version            StatusVersion       `json:"version"`
+	Players            StatusPlayers       `json:"players"`
+	Description        component.Component `json:"description"`
+	Favicon            string              `json:"favicon,omitempty"` // data:image/png;base64 URI of a 64x64 PNG
+	EnforcesSecureChat bool                `json:"enforcesSecureChat"`
+}
+
+// StatusVersion is the version shown in the server list. Clients with a different
+// protocol show Name in red.
+type StatusVersion struct {
+	Name     string `json:"name"`
+	Protocol int32  `json:"protocol"`
+}
+
+// StatusPlayers is the player count, and the names shown when hovering over it.
+type StatusPlayers struct {
+	Max    int32          `json:"max"`
+	Online int32          `json:"online"`
+	Sample []StatusSample `json:"sample,omitempty"`
+}
+
+// StatusSample is one entry of the player list tooltip.
+type StatusSample struct {
+	Name string    `json:"name"`
+	ID   uuid.UUID `json:"id"`
+}
 
 // StatusResponsePacket represents a status response to a StatusRequestPacket
-type StatusResponsePacket struct { /*No fields Atm*/
+type StatusResponsePacket struct {
+	Response StatusResponse
 }
 
 func (p *StatusResponsePacket) ID() int32 {
@@ -35,5 +46,6 @@ func (p *StatusResponsePacket) ID() int32 {
 }
 
 func (p *StatusResponsePacket) Write(buf *common.Buffer) {
-	buf.WriteString(JsonExample)
+	data, _ := json.Marshal(&p.Response)
+	buf.WriteString(string(data))
 }
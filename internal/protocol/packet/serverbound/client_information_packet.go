@@ -1,11 +1,11 @@
 package serverbound
 
-import (
-	"Veloce/internal/network/common"
-)
+// maxLocaleLength is the longest locale vanilla accepts, such as "en_us".
+const maxLocaleLength = 16
 
+//packet:configuration 0x00
 type ClientInformationPacket struct {
-	locale        string
+	locale        string `mc:",max=maxLocaleLength"`
 	render        byte
 	chatMode      int32
 	chatColor     bool
@@ -15,23 +15,3 @@ type ClientInformationPacket struct {
 	serverListing bool
 	particle      int32
 }
-
-func (p *ClientInformationPacket) ID() int32 {
-	return 0x00
-}
-
-func (p *ClientInformationPacket) Read(buf *common.Buffer) {
-	p.locale, _ = buf.ReadString()
-	p.render, _ = buf.ReadByte()
-	p.chatMode, _ = buf.ReadVarInt()
-	p.chatColor, _ = buf.ReadBool()
-	p.skin, _ = buf.ReadByte()
-	p.mainHand, _ = buf.ReadVarInt()
-	p.filter, _ = buf.ReadBool()
-	p.serverListing, _ = buf.ReadBool()
-	p.particle, _ = buf.ReadVarInt()
-}
-
-func (p *ClientInformationPacket) Handle(pc *common.PlayerConnection) {
-	// Nothing to handle
-}
@@ -1,21 +1,6 @@
 package serverbound
 
-import (
-	common2 "Veloce/internal/network/common"
-)
-
+//packet:play 0x00
 type ConfirmTeleportationPacket struct {
 	TeleportId int32
 }
-
-func (c ConfirmTeleportationPacket) ID() int32 {
-	return 0x00
-}
-
-func (c ConfirmTeleportationPacket) Read(buf *common2.Buffer) {
-	c.TeleportId, _ = buf.ReadVarInt()
-}
-
-func (c ConfirmTeleportationPacket) Handle(*common2.PlayerConnection) {
-	// No Handling
-}
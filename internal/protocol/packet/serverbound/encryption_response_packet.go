@@ -0,0 +1,10 @@
+package serverbound
+
+// maxEncryptedLength bounds the RSA blocks a client may send; 1024-bit keys produce 128 bytes.
+const maxEncryptedLength = 256
+
+//packet:login 0x01
+type EncryptionResponsePacket struct {
+	SharedSecret []byte `mc:",max=maxEncryptedLength"`
+	VerifyToken  []byte `mc:",max=maxEncryptedLength"`
+}
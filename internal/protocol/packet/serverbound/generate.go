@@ -0,0 +1,5 @@
+// Package serverbound holds the packets clients send. Their ID and Read methods, and their
+// registration, are generated from the struct definitions by packetgen.
+package serverbound
+
+//go:generate go run Veloce/cmd/packetgen
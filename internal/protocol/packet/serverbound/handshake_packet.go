@@ -1,27 +1,16 @@
 package serverbound
 
-import (
-	"Veloce/internal/network/common"
+// Handshake intents, the values of HandshakePacket.NextState.
+const (
+	IntentStatus   = 1
+	IntentLogin    = 2
+	IntentTransfer = 3 // Login after another server sent the client here with a Transfer packet
 )
 
+//packet:handshake 0x00
 type HandshakePacket struct {
 	ProtocolVersion int32
-	ServerAddress   string
+	ServerAddress   string `mc:",max=common.MaxStringLength"` // More than vanilla's 255 to fit BungeeCord forwarding
 	ServerPort      uint16
 	NextState       int32
 }
-
-func (h *HandshakePacket) ID() int32 {
-	return 0x00
-}
-
-func (h *HandshakePacket) Read(buf *common.Buffer) {
-	h.ProtocolVersion, _ = buf.ReadVarInt()
-	h.ServerAddress, _ = buf.ReadString()
-	h.ServerPort, _ = buf.ReadUint16()
-	h.NextState, _ = buf.ReadVarInt()
-}
-
-func (h *HandshakePacket) Handle(pc *common.PlayerConnection) {
-	pc.SetState(common.ConnectionState(h.NextState))
-}
@@ -0,0 +1,15 @@
+package serverbound
+
+// ConfigurationKeepAlivePacket echoes the ID of a Keep Alive sent during Configuration.
+//
+//packet:configuration 0x04
+type ConfigurationKeepAlivePacket struct {
+	KeepAliveID int64
+}
+
+// PlayKeepAlivePacket echoes the ID of a Keep Alive sent during Play.
+//
+//packet:play 0x1A
+type PlayKeepAlivePacket struct {
+	KeepAliveID int64
+}
@@ -0,0 +1,11 @@
+package serverbound
+
+// LoginPluginResponsePacket answers a Login Plugin Request. Understood is false, and Data
+// empty, if the client does not know the channel.
+//
+//packet:login 0x02
+type LoginPluginResponsePacket struct {
+	MessageID  int32
+	Understood bool
+	Data       []byte `mc:"rest"`
+}
@@ -1,25 +1,11 @@
 package serverbound
 
 import (
-	common2 "Veloce/internal/network/common"
-	"Veloce/internal/protocol/packet/clientbound"
 	"github.com/google/uuid"
 )
 
+//packet:login 0x00
 type LoginStartPacket struct {
-	Username string
+	Username string `mc:",max=common.MaxProfileNameLength"`
 	Uuid     uuid.UUID
 }
-
-func (h *LoginStartPacket) ID() int32 {
-	return 0x00
-}
-
-func (h *LoginStartPacket) Read(buf *common2.Buffer) {
-	h.Username, _ = buf.ReadString()
-	h.Uuid, _ = buf.ReadUUID()
-}
-
-func (h *LoginStartPacket) Handle(pc *common2.PlayerConnection) {
-	_ = pc.SendPacket(&clientbound.LoginSuccessPacket{})
-}
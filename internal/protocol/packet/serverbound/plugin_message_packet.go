@@ -1,23 +1,10 @@
 package serverbound
 
-import (
-	common2 "Veloce/internal/network/common"
-)
+// maxPluginMessageSize is the most data vanilla accepts in a serverbound plugin message.
+const maxPluginMessageSize = 32767
 
+//packet:configuration 0x02
 type PluginMessagePacket struct {
 	identifier string
-	data       []byte
-}
-
-func (p *PluginMessagePacket) ID() int32 {
-	return 0x02
-}
-
-func (p *PluginMessagePacket) Read(buf *common2.Buffer) {
-	p.identifier, _ = buf.ReadString()
-	p.data = buf.Bytes()
-}
-
-func (p *PluginMessagePacket) Handle(*common2.PlayerConnection) {
-	// Nothing to handle
+	data       []byte `mc:"rest,max=maxPluginMessageSize"`
 }
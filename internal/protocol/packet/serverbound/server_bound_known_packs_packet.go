@@ -1,50 +1,13 @@
 package serverbound
 
 import (
-	common2 "Veloce/internal/network/common"
 	"Veloce/internal/protocol/packet/clientbound"
-	"fmt"
-	"os"
-	"path/filepath"
 )
 
-type ServerBoundKnownPacksPacket struct {
-	//TODO
-}
-
-func (p *ServerBoundKnownPacksPacket) ID() int32 {
-	return 0x07
-}
-
-func (p *ServerBoundKnownPacksPacket) Read(*common2.Buffer) {
-	// Nothing to read
-}
+// maxKnownPacks is the most packs vanilla accepts in a known packs reply.
+const maxKnownPacks = 64
 
-func (p *ServerBoundKnownPacksPacket) Handle(pc *common2.PlayerConnection) {
-	for i := 0; i <= 19; i++ {
-		data, err := ReadLineBinFile(i)
-		if err != nil {
-			fmt.Printf("failed to read line_%d.bin: %v\n", i, err)
-			continue // skip this one and keep going
-		}
-
-		if err := pc.SendRaw(data); err != nil {
-			fmt.Printf("failed to send raw data for line_%d.bin: %v\n", i, err)
-			continue
-		}
-	}
-
-	// After sending raw bin files, send the actual configuration packet
-	packet := &clientbound.FinishConfigurationPacket{}
-	_ = pc.SendPacket(packet)
-}
-
-// ReadLineBinFile reads the content of "reg/line_<index>.bin" and returns it as []byte.
-func ReadLineBinFile(index int) ([]byte, error) {
-	filename := filepath.Join("reg", fmt.Sprintf("line_%d.bin", index))
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
-	}
-	return data, nil
+//packet:configuration 0x07
+type ServerBoundKnownPacksPacket struct {
+	KnownPacks []clientbound.KnownPack `mc:",max=maxKnownPacks"`
 }
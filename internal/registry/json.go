@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"Veloce/internal/network/common"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// jsonEntry is either a bare identifier string or an object with an id and optional data.
+type jsonEntry struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (e *jsonEntry) UnmarshalJSON(raw []byte) error {
+	if len(raw) > 0 && raw[0] == '"' {
+		return json.Unmarshal(raw, &e.ID)
+	}
+	type plain jsonEntry
+	return json.Unmarshal(raw, (*plain)(e))
+}
+
+// loadJSON registers every entry of a registry file into r.
+func loadJSON(r *Registry, raw []byte, knownPack bool) error {
+	var file struct {
+		Entries []jsonEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return err
+	}
+
+	for _, entry := range file.Entries {
+		var data common.Tag
+		if len(entry.Data) > 0 && string(entry.Data) != "null" {
+			var err error
+			data, err = JSONToNBT(entry.Data)
+			if err != nil {
+				return fmt.Errorf("entry %s: %w", entry.ID, err)
+			}
+		}
+		if err := r.register(entry.ID, data, knownPack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JSONToNBT converts JSON into an NBT tag the way vanilla's codecs would read it: booleans
+// become bytes, integral numbers become ints (or longs when they overflow), other numbers
+// become doubles, and arrays become lists of a single element type.
+func JSONToNBT(raw []byte) (common.Tag, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return jsonValueToNBT(v)
+}
+
+func jsonValueToNBT(v any) (common.Tag, error) {
+	switch t := v.(type) {
+	case bool:
+		return common.BoolTag(t), nil
+	case string:
+		return common.StringTag(t), nil
+	case json.Number:
+		if !strings.ContainsAny(t.String(), ".eE") {
+			if n, err := t.Int64(); err == nil {
+				if n >= math.MinInt32 && n <= math.MaxInt32 {
+					return common.IntTag(n), nil
+				}
+				return common.LongTag(n), nil
+			}
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return common.DoubleTag(f), nil
+	case []any:
+		list := &common.ListTag{}
+		for _, elem := range t {
+			tag, err := jsonValueToNBT(elem)
+			if err != nil {
+				return nil, err
+			}
+			if err := list.Add(tag); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	case map[string]any:
+		compound := make(common.CompoundTag, len(t))
+		for key, elem := range t {
+			if elem == nil {
+				continue
+			}
+			tag, err := jsonValueToNBT(elem)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			compound[key] = tag
+		}
+		return compound, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value %T", v)
+	}
+}
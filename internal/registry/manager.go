@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"Veloce/internal/protocol/packet/clientbound"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+//go:embed data
+var vanillaData embed.FS
+
+const vanillaRoot = "data"
+
+// Manager holds every registry that is synchronised to clients during configuration,
+// in the order they are sent.
+type Manager struct {
+	knownPacks []clientbound.KnownPack
+	registries []*Registry
+	byID       map[string]*Registry
+	mu         sync.RWMutex
+}
+
+// NewManager creates a manager without any registries.
+func NewManager() *Manager {
+	return &Manager{
+		byID: make(map[string]*Registry),
+	}
+}
+
+// NewVanillaManager creates a manager populated from the embedded vanilla data.
+func NewVanillaManager() (*Manager, error) {
+	m := NewManager()
+	if err := m.load(vanillaData, vanillaRoot, true); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load reads registries from fsys. The root directory must contain a registries.json listing
+// registry identifiers in send order; each registry is read from <root>/<path>.json, where
+// path is the identifier without its namespace (e.g. worldgen/biome.json).
+// Entries that already exist are overridden.
+func (m *Manager) Load(fsys fs.FS, root string) error {
+	return m.load(fsys, root, false)
+}
+
+// load reads registries from fsys. When knownPack is set, the index's pack is recorded as known
+// and its entries may be sent without data to clients that have it.
+func (m *Manager) load(fsys fs.FS, root string, knownPack bool) error {
+	raw, err := fs.ReadFile(fsys, path.Join(root, "registries.json"))
+	if err != nil {
+		return fmt.Errorf("reading registry index: %w", err)
+	}
+
+	var index struct {
+		Pack *struct {
+			Namespace string `json:"namespace"`
+			ID        string `json:"id"`
+			Version   string `json:"version"`
+		} `json:"pack"`
+		Registries []string `json:"registries"`
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return fmt.Errorf("parsing registry index: %w", err)
+	}
+
+	knownPack = knownPack && index.Pack != nil
+	if knownPack {
+		m.mu.Lock()
+		m.knownPacks = append(m.knownPacks, clientbound.KnownPack{
+			Namespace: index.Pack.Namespace,
+			ID:        index.Pack.ID,
+			Version:   index.Pack.Version,
+		})
+		m.mu.Unlock()
+	}
+
+	for _, id := range index.Registries {
+		_, value, found := strings.Cut(id, ":")
+		if !found {
+			value = id
+		}
+		file := path.Join(root, value+".json")
+		raw, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("reading registry %s: %w", id, err)
+		}
+		if err := loadJSON(m.GetOrCreate(id), raw, knownPack); err != nil {
+			return fmt.Errorf("loading %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// GetOrCreate returns the registry with the given identifier, appending an empty one if needed.
+func (m *Manager) GetOrCreate(id string) *Registry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.byID[id]; ok {
+		return r
+	}
+	r := NewRegistry(id)
+	m.byID[id] = r
+	m.registries = append(m.registries, r)
+	return r
+}
+
+// Get returns the registry with the given identifier.
+func (m *Manager) Get(id string) (*Registry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	r, ok := m.byID[id]
+	return r, ok
+}
+
+// Registries returns all registries in send order.
+func (m *Manager) Registries() []*Registry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	registries := make([]*Registry, len(m.registries))
+	copy(registries, m.registries)
+	return registries
+}
+
+// KnownPacks returns the data packs the server offers during known-packs negotiation.
+func (m *Manager) KnownPacks() []clientbound.KnownPack {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	packs := make([]clientbound.KnownPack, len(m.knownPacks))
+	copy(packs, m.knownPacks)
+	return packs
+}
+
+// SharesKnownPacks reports whether clientPacks contains every pack the server offered,
+// with matching versions, so known entries can be sent without data.
+func (m *Manager) SharesKnownPacks(clientPacks []clientbound.KnownPack) bool {
+	known := m.KnownPacks()
+	if len(known) == 0 {
+		return false
+	}
+	for _, pack := range known {
+		if !slices.Contains(clientPacks, pack) {
+			return false
+		}
+	}
+	return true
+}
+
+// Packets builds one Registry Data packet per registry, in send order. When omitKnown is set,
+// entries from known packs are sent without data. It fails if an entry that has to be sent
+// in full has no data.
+func (m *Manager) Packets(omitKnown bool) ([]*clientbound.RegistryDataPacket, error) {
+	registries := m.Registries()
+	packets := make([]*clientbound.RegistryDataPacket, len(registries))
+	for i, r := range registries {
+		packet, err := r.Packet(omitKnown)
+		if err != nil {
+			return nil, err
+		}
+		packets[i] = packet
+	}
+	return packets, nil
+}
@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"Veloce/internal/network/common"
+	"Veloce/internal/objects/identifier"
+	"Veloce/internal/protocol/packet/clientbound"
+	"fmt"
+	"sync"
+)
+
+// Entry is a single registry entry. An entry without Data can only be sent to clients that
+// know it from a known pack.
+type Entry struct {
+	ID   string
+	Data common.Tag
+	// KnownPack is set for unmodified entries of a known pack, which may be sent without data
+	// to clients that report having that pack.
+	KnownPack bool
+}
+
+// Registry is an ordered, synchronised registry such as minecraft:dimension_type.
+// An entry's network ID is its position in the registry.
+type Registry struct {
+	id      string
+	entries []Entry
+	index   map[string]int
+	mu      sync.RWMutex
+}
+
+// NewRegistry creates an empty registry with the given identifier.
+func NewRegistry(id string) *Registry {
+	return &Registry{
+		id:    id,
+		index: make(map[string]int),
+	}
+}
+
+// ID returns the registry identifier.
+func (r *Registry) ID() string {
+	return r.id
+}
+
+// Register adds an entry, or replaces the data of an existing entry while keeping its network ID.
+// Registered entries are always sent with their data.
+func (r *Registry) Register(id string, data common.Tag) error {
+	return r.register(id, data, false)
+}
+
+func (r *Registry) register(id string, data common.Tag, knownPack bool) error {
+	parsed, err := identifier.ParseIdentifier(id)
+	if err != nil {
+		return fmt.Errorf("registry %s: entry %q: %w", r.id, id, err)
+	}
+	id = parsed.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.index[id]; ok {
+		r.entries[i].Data = data
+		r.entries[i].KnownPack = knownPack
+		return nil
+	}
+	r.index[id] = len(r.entries)
+	r.entries = append(r.entries, Entry{ID: id, Data: data, KnownPack: knownPack})
+	return nil
+}
+
+// RegisterValue marshals v to NBT and registers it, see common.MarshalNBT.
+func (r *Registry) RegisterValue(id string, v any) error {
+	data, err := common.MarshalNBT(v)
+	if err != nil {
+		return fmt.Errorf("registry %s: entry %q: %w", r.id, id, err)
+	}
+	return r.Register(id, data)
+}
+
+// Remove deletes an entry. Later entries shift down, changing their network IDs.
+func (r *Registry) Remove(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.index[id]
+	if !ok {
+		return false
+	}
+	r.entries = append(r.entries[:i], r.entries[i+1:]...)
+	delete(r.index, id)
+	for j := i; j < len(r.entries); j++ {
+		r.index[r.entries[j].ID] = j
+	}
+	return true
+}
+
+// Get returns the entry with the given identifier.
+func (r *Registry) Get(id string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.index[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return r.entries[i], true
+}
+
+// NetworkID returns the protocol ID of the entry with the given identifier.
+func (r *Registry) NetworkID(id string) (int32, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.index[id]
+	return int32(i), ok
+}
+
+// Len returns the number of entries.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
+
+// Entries returns a copy of the entries in network ID order.
+func (r *Registry) Entries() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Packet builds the Registry Data packet for this registry. When omitKnown is set, entries
+// that come from a known pack are sent without data. Any other entry must have data, since
+// the client has no way to fill it in.
+func (r *Registry) Packet(omitKnown bool) (*clientbound.RegistryDataPacket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]clientbound.RegistryEntry, len(r.entries))
+	for i, e := range r.entries {
+		if omitKnown && e.KnownPack {
+			entries[i] = clientbound.RegistryEntry{ID: e.ID}
+			continue
+		}
+		if e.Data == nil {
+			return nil, fmt.Errorf("registry %s: entry %s has no data", r.id, e.ID)
+		}
+		entries[i] = clientbound.RegistryEntry{ID: e.ID, Data: e.Data}
+	}
+	return &clientbound.RegistryDataPacket{RegistryID: r.id, Entries: entries}, nil
+}
@@ -0,0 +1,68 @@
+package registry
+
+// Identifiers of the registries synchronised during configuration.
+const (
+	Biome            = "minecraft:worldgen/biome"
+	ChatType         = "minecraft:chat_type"
+	TrimPattern      = "minecraft:trim_pattern"
+	TrimMaterial     = "minecraft:trim_material"
+	WolfVariant      = "minecraft:wolf_variant"
+	WolfSoundVariant = "minecraft:wolf_sound_variant"
+	PigVariant       = "minecraft:pig_variant"
+	FrogVariant      = "minecraft:frog_variant"
+	CatVariant       = "minecraft:cat_variant"
+	CowVariant       = "minecraft:cow_variant"
+	ChickenVariant   = "minecraft:chicken_variant"
+	PaintingVariant  = "minecraft:painting_variant"
+	DimensionType    = "minecraft:dimension_type"
+	DamageType       = "minecraft:damage_type"
+	BannerPattern    = "minecraft:banner_pattern"
+	JukeboxSong      = "minecraft:jukebox_song"
+	Instrument       = "minecraft:instrument"
+	TestEnvironment  = "minecraft:test_environment"
+	TestInstance     = "minecraft:test_instance"
+)
+
+// DimensionTypeData is the NBT layout of a minecraft:dimension_type entry.
+type DimensionTypeData struct {
+	FixedTime                   *int64  `nbt:"fixed_time,omitempty"`
+	HasSkylight                 bool    `nbt:"has_skylight"`
+	HasCeiling                  bool    `nbt:"has_ceiling"`
+	Ultrawarm                   bool    `nbt:"ultrawarm"`
+	Natural                     bool    `nbt:"natural"`
+	CoordinateScale             float64 `nbt:"coordinate_scale"`
+	BedWorks                    bool    `nbt:"bed_works"`
+	RespawnAnchorWorks          bool    `nbt:"respawn_anchor_works"`
+	MinY                        int32   `nbt:"min_y"`
+	Height                      int32   `nbt:"height"`
+	LogicalHeight               int32   `nbt:"logical_height"`
+	Infiniburn                  string  `nbt:"infiniburn"`
+	Effects                     string  `nbt:"effects"`
+	AmbientLight                float32 `nbt:"ambient_light"`
+	PiglinSafe                  bool    `nbt:"piglin_safe"`
+	HasRaids                    bool    `nbt:"has_raids"`
+	MonsterSpawnLightLevel      int32   `nbt:"monster_spawn_light_level"`
+	MonsterSpawnBlockLightLimit int32   `nbt:"monster_spawn_block_light_limit"`
+}
+
+// BiomeData is the NBT layout of a minecraft:worldgen/biome entry, limited to the fields
+// the client reads.
+type BiomeData struct {
+	HasPrecipitation    bool         `nbt:"has_precipitation"`
+	Temperature         float32      `nbt:"temperature"`
+	TemperatureModifier string       `nbt:"temperature_modifier,omitempty"`
+	Downfall            float32      `nbt:"downfall"`
+	Effects             BiomeEffects `nbt:"effects"`
+}
+
+// BiomeEffects holds the client-side visual settings of a biome. Colours are packed RGB.
+type BiomeEffects struct {
+	FogColor           int32  `nbt:"fog_color"`
+	WaterColor         int32  `nbt:"water_color"`
+	WaterFogColor      int32  `nbt:"water_fog_color"`
+	SkyColor           int32  `nbt:"sky_color"`
+	FoliageColor       int32  `nbt:"foliage_color,omitempty"`
+	DryFoliageColor    int32  `nbt:"dry_foliage_color,omitempty"`
+	GrassColor         int32  `nbt:"grass_color,omitempty"`
+	GrassColorModifier string `nbt:"grass_color_modifier,omitempty"`
+}